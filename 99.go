@@ -1,18 +1,181 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
 	"shuffle/cards"
+	"shuffle/render"
+	"strings"
 )
 
-// Main initializes a single round of 99 with 4 players.
+// Main initializes a single round of the chosen game with 4 players.
 // The current implementation is a simple proof of concept.
 // Players are currently all controlled by the same person (you) via the command line.
 // All cards are temporarily disclosed for debugging purposes.
 func main() {
-	names := []string{"Alice", "Bob", "Charlie", "Dan"}
-	players := initializePlayers(names)
-	mgr := new(cards.NNGameManager)
-	mgr.NewGame(players)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [99 | crazy8s | blackjack | simulate | tournament | leaderboard | bot | serve | spectate <address>]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	hints := flag.Bool("hints", false, "show beginner hints about each card in 99")
+	profiles := flag.String("profiles", "", "file where player profiles are kept under the names given by -players, e.g. "+cards.DefaultProfilePath())
+	playerList := flag.String("players", "", "comma-separated names of the people playing 99 or crazy8s, e.g. Ann,Ben (defaults to four made-up names)")
+	plain := flag.Bool("plain", false, "play 99 on the plain command line instead of the full-screen interface")
+	undo := flag.Bool("undo", false, "let players take back their last card in 99 before the next player acts")
+	lang := flag.String("lang", "", "the language to play in: en, it or fr (defaults to the language of LANG)")
+	style := flag.String("cards", string(render.Compact), "how cards are drawn on the plain command line: compact, boxes, glyphs or names")
+	accessible := flag.Bool("accessible", false, "describe 99 in plain sentences with full card names, for screen readers")
+	fourColour := flag.Bool("fourcolor", false, "draw each suit in its own high-contrast colour (unless NO_COLOR is set)")
+	logPath := flag.String("log", "", "file to append the game's events to, as JSON Lines")
+	spectators := flag.String("spectate", "", "address to let spectators watch 99 from, e.g. :9999")
+	robots := flag.Int("robots", 0, "how many robots play 99 and crazy8s, after the -players or in the last of the four made-up seats")
+	commentary := flag.Duration("commentary", 0, "reveal every hand to spectators after this delay, e.g. 30s (0 reveals none)")
+	flag.Parse()
+
+	cards.SetLanguage(cards.LanguageFromEnv())
+	if *lang != "" {
+		tag, ok := cards.MatchLanguage(*lang)
+		if !ok {
+			exitOnError(fmt.Errorf("unsupported language %q", *lang))
+		}
+		cards.SetLanguage(tag)
+	}
+
+	cardStyle, err := render.ParseStyle(*style)
+	exitOnError(err)
+	if *accessible {
+		cardStyle = render.Names
+	}
+	cards.SetCardStyle(cardStyle)
+	if *fourColour && render.DefaultPalette() != render.NoColour {
+		cards.SetCardPalette(render.FourColour)
+	}
+
+	events := cards.NewEventStream()
+	if *logPath != "" {
+		log, err := cards.OpenEventLog(*logPath)
+		exitOnError(err)
+		events.Subscribe(log.Record)
+		defer func() { exitOnError(log.Close()) }()
+	}
+
+	names, err := playerNames(*playerList, *robots)
+	exitOnError(err)
+	switch game := flag.Arg(0); game {
+	case "", "99":
+		mgr := new(cards.NNGameManager)
+		mgr.SetEvents(events)
+		mgr.SetHints(*hints)
+		mgr.SetFullScreen(!*plain)
+		mgr.SetNarration(*accessible)
+		players := initializePlayers(names)
+		var store *cards.ProfileStore
+		if *profiles != "" {
+			if *playerList == "" {
+				exitOnError(fmt.Errorf("-profiles needs -players, since profiles are kept under each player's name"))
+			}
+			store, err = cards.OpenProfileStore(*profiles)
+			exitOnError(err)
+			exitOnError(linkProfiles(store, players))
+			mgr.SetProfiles(store)
+		}
+		if *spectators != "" {
+			l, err := net.Listen("tcp", *spectators)
+			exitOnError(err)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cfg := cards.SpectatorConfig{Commentary: *commentary > 0, Delay: *commentary}
+			go func() { exitOnError(cards.ServeSpectators(ctx, l, mgr, cfg)) }()
+		}
+		settings := *cards.NNDefaultSettings
+		settings.Undo = *undo
+		mgr.NewGameWithSettings(players, *robots, &settings)
+		if store != nil {
+			exitOnError(store.Err())
+		}
+	case "crazy8s":
+		mgr := new(cards.CEGameManager)
+		mgr.SetEvents(events)
+		mgr.NewGameWithRobots(initializeCEPlayers(names), *robots)
+	case "blackjack":
+		mgr := new(cards.BJGameManager)
+		mgr.SetEvents(events)
+		mgr.NewGame(initializeBJPlayers(names))
+	case "simulate":
+		exitOnError(simulate(flag.Args()[1:]))
+	case "tournament":
+		exitOnError(tournament(flag.Args()[1:]))
+	case "leaderboard":
+		if *profiles == "" {
+			exitOnError(fmt.Errorf("leaderboard needs -profiles"))
+		}
+		store, err := cards.OpenProfileStore(*profiles)
+		exitOnError(err)
+		exitOnError(store.WriteLeaderboard(os.Stdout))
+	case "bot":
+		exitOnError(bot(flag.Args()[1:]))
+	case "serve":
+		exitOnError(serve(flag.Args()[1:]))
+	case "spectate":
+		exitOnError(spectate(flag.Arg(1)))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// exitOnError prints the error and exits, if there is one.
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// spectate watches a game of 99 being played at the address, until it is over.
+func spectate(addr string) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = io.Copy(os.Stdout, conn)
+	return err
+}
+
+// playerNames returns the names of the human players: those in the comma-separated list,
+// or else as many of four made-up names as leave room for the robots.
+func playerNames(list string, robots int) ([]string, error) {
+	if list == "" {
+		names := []string{"Alice", "Bob", "Charlie", "Dan"}
+		if robots < 0 || robots > len(names) {
+			return nil, fmt.Errorf("-robots must be 0-%d", len(names))
+		}
+		return names[:len(names)-robots], nil
+	}
+	if robots < 0 {
+		return nil, fmt.Errorf("-robots must not be negative")
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("-players must not have empty names")
+		} else if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("-players names %q more than once", name)
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	if len(names)+robots < 2 {
+		return nil, fmt.Errorf("-players and -robots must make at least two players")
+	}
+	return names, nil
 }
 
 // InitializePlayers is a factory that creates players with the given names.
@@ -22,3 +185,31 @@ func initializePlayers(names []string) (players []*cards.NNPlayer) {
 	}
 	return players
 }
+
+// linkProfiles links each player to the profile with their name, creating profiles for newcomers.
+func linkProfiles(store *cards.ProfileStore, players []*cards.NNPlayer) error {
+	for _, p := range players {
+		prof, err := store.FindOrCreate(p.Name())
+		if err != nil {
+			return err
+		}
+		p.SetProfile(prof.ID)
+	}
+	return nil
+}
+
+// InitializeCEPlayers is a factory that creates Crazy Eights players with the given names.
+func initializeCEPlayers(names []string) (players []*cards.CEPlayer) {
+	for _, name := range names {
+		players = append(players, cards.NewCEPlayer(name))
+	}
+	return players
+}
+
+// InitializeBJPlayers is a factory that creates Blackjack players with the given names.
+func initializeBJPlayers(names []string) (players []*cards.BJPlayer) {
+	for _, name := range names {
+		players = append(players, cards.NewBJPlayer(name))
+	}
+	return players
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"shuffle/cards"
+	"strings"
+)
+
+// bot runs a built-in strategy as an external bot, speaking the 99 bot protocol over stdin and stdout.
+// It is the reference bot for anyone writing their own.
+func bot(args []string) error {
+	fs := flag.NewFlagSet("bot", flag.ExitOnError)
+	strategy := fs.String("strategy", "cautious", "strategy to play: "+strings.Join(cards.NNStrategyNames(), ", "))
+	seed := fs.Int64("seed", 1, "seed for the strategy's decisions")
+	fs.Parse(args)
+
+	s, err := cards.NewNNStrategy(*strategy, *seed)
+	if err != nil {
+		return err
+	}
+	return cards.ServeBot(context.Background(), os.Stdin, os.Stdout, s)
+}
@@ -0,0 +1,552 @@
+package cards
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// BJGameSettings holds the relevant settings for a game of Blackjack.
+type BJGameSettings struct {
+	NumDecks         int
+	StartingChips    int
+	MinBet           int
+	HitSoft17        bool // whether the house hits, rather than stands, on a soft 17
+	BlackjackPays    int  // the numerator of the payout for a natural blackjack
+	BlackjackPer     int  // the denominator of the payout for a natural blackjack
+	MaxSplitHands    int
+	DoubleAfterSplit bool
+}
+
+// BJDefaultSettings are the "house rules" for a game of Blackjack.
+var BJDefaultSettings = &BJGameSettings{
+	NumDecks:         6,
+	StartingChips:    100,
+	MinBet:           1,
+	HitSoft17:        false,
+	BlackjackPays:    3,
+	BlackjackPer:     2,
+	MaxSplitHands:    4,
+	DoubleAfterSplit: true,
+}
+
+// Game returns the name of the game these Rules are for.
+func (set *BJGameSettings) Game() string {
+	return "Blackjack"
+}
+
+// HandSize returns the number of cards dealt to each Player.
+func (set *BJGameSettings) HandSize() int {
+	return 2
+}
+
+// Decks returns the number of decks to use in the Shoe, regardless of the number of Players.
+func (set *BJGameSettings) Decks(numPlayers int) int {
+	return set.NumDecks
+}
+
+// BJHand is a single Blackjack hand and the chips wagered on it.
+// A Player holds more than one BJHand only after splitting.
+type BJHand struct {
+	Cards   Hand
+	Bet     int
+	Doubled bool
+	Split   bool
+	Done    bool
+}
+
+// Total returns the best total of the hand without busting, if possible.
+// Aces count as 11 unless that would bust the hand, in which case they count as 1.
+// The total is soft if an ace is being counted as 11.
+func (h BJHand) Total() (total int, soft bool) {
+	return handTotal(h.Cards)
+}
+
+// Blackjack returns true if the hand is a natural: an ace and a ten-valued card that were not split.
+func (h BJHand) Blackjack() bool {
+	total, _ := h.Total()
+	return len(h.Cards) == 2 && total == 21 && !h.Split
+}
+
+// Bust returns true if the hand totals more than 21.
+func (h BJHand) Bust() bool {
+	total, _ := h.Total()
+	return total > 21
+}
+
+// handTotal calculates the Blackjack total of a collection of cards.
+func handTotal(cards []Card) (total int, soft bool) {
+	aces := 0
+	for _, c := range cards {
+		switch c.rank {
+		case Ace:
+			aces++
+			total++
+		case Jack, Queen, King:
+			total += 10
+		default:
+			v, _ := strconv.Atoi(string(c.rank))
+			total += v
+		}
+	}
+	if aces > 0 && total+10 <= 21 {
+		return total + 10, true
+	}
+	return total, false
+}
+
+// BJPlayer is an implementation of Player designed to play Blackjack against the house.
+// It wagers chips from a bankroll, and may hold several hands after splitting.
+type BJPlayer struct {
+	player
+	chips  int
+	hands  []*BJHand
+	active int
+}
+
+// NewBJPlayer creates a Blackjack player with the given name.
+// The player receives their bankroll when they sit down at a table.
+func NewBJPlayer(name string) *BJPlayer {
+	p := new(BJPlayer)
+	p.name = name
+	return p
+}
+
+// Chips returns the number of chips in the Player's bankroll, excluding chips currently wagered.
+func (p *BJPlayer) Chips() int {
+	return p.chips
+}
+
+// Hands returns a copy of every hand the Player holds this round.
+func (p *BJPlayer) Hands() []BJHand {
+	h := make([]BJHand, len(p.hands))
+	for i, hand := range p.hands {
+		h[i] = *hand
+		h[i].Cards = append(Hand(nil), hand.Cards...)
+	}
+	return h
+}
+
+// Hand returns a copy of the hand the Player is currently playing.
+func (p *BJPlayer) Hand() Hand {
+	if hand := p.activeHand(); hand != nil {
+		return append(Hand{}, hand.Cards...)
+	}
+	return Hand{}
+}
+
+// AcceptCards adds more Cards to the hand the Player is currently playing.
+func (p *BJPlayer) AcceptCards(h Hand) {
+	if hand := p.activeHand(); hand != nil {
+		hand.Cards = append(hand.Cards, h...)
+	}
+}
+
+// ReplaceHand discards any split hands and replaces the Player's hand with an entirely new Hand.
+// The new hand keeps the wager of the Player's first hand.
+func (p *BJPlayer) ReplaceHand(h Hand) {
+	bet := 0
+	if len(p.hands) > 0 {
+		bet = p.hands[0].Bet
+	}
+	p.hands = []*BJHand{{Cards: h, Bet: bet}}
+	p.active = 0
+}
+
+// removeCard removes a Card from the hand the Player is currently playing, if it exists.
+func (p *BJPlayer) removeCard(c Card) bool {
+	if hand := p.activeHand(); hand != nil {
+		for i, card := range hand.Cards {
+			if card == c {
+				hand.Cards = append(hand.Cards[:i:i], hand.Cards[i+1:]...)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// activeHand returns the hand the Player is currently playing, or nil if they hold none.
+func (p *BJPlayer) activeHand() *BJHand {
+	if 0 <= p.active && p.active < len(p.hands) {
+		return p.hands[p.active]
+	}
+	return nil
+}
+
+// BJGameManager is an implementation of GameManager that deals Blackjack.
+// Each round, Players bet chips, then take turns hitting, standing, doubling down or splitting
+// against the house's hand. The house draws by fixed rules and pays out winning hands.
+// The Shoe is kept between rounds and reshuffled whenever it runs out.
+type BJGameManager struct {
+	settings *BJGameSettings
+	table    *Table
+	dealer   *dealer
+	events   *EventStream
+	playing  bool
+	dealt    bool
+	house    Hand
+}
+
+// StartGame seats the players, gives each their bankroll, and prepares a fresh Shoe.
+// Play begins once bets are placed and the first hand is dealt.
+func (mgr *BJGameManager) StartGame(players []*BJPlayer, settings *BJGameSettings) {
+	if settings != nil {
+		mgr.settings = settings
+	} else {
+		mgr.settings = BJDefaultSettings
+	}
+	if mgr.events == nil {
+		mgr.events = NewEventStream()
+	}
+	mgr.table = NewTable()
+	for _, p := range players {
+		mgr.table.Sit(p, mgr)
+		p.chips = mgr.settings.StartingChips
+		p.hands = nil
+	}
+	mgr.dealer = NewDealer(mgr.settings.Decks(len(players)), NewRng(), true)
+	mgr.house = nil
+	mgr.dealt = false
+	mgr.playing = true
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventGameCreated, Seat: NoSeat, Names: tableNames(mgr.table)})
+}
+
+// Bet wagers chips from a Player's bankroll on the next hand.
+// Players who do not bet sit out the hand.
+func (mgr *BJGameManager) Bet(p Player, amount int) error {
+	bj, err := mgr.bjPlayer(p)
+	if err != nil {
+		return err
+	} else if mgr.dealt {
+		return errors.New("bets are closed until the hand is over")
+	} else if amount < mgr.settings.MinBet {
+		return errors.Errorf("the minimum bet is %d", mgr.settings.MinBet)
+	} else if amount > bj.chips {
+		return errors.New("not enough chips")
+	}
+	if len(bj.hands) == 0 {
+		bj.hands = []*BJHand{{}}
+	}
+	bj.chips += bj.hands[0].Bet - amount
+	bj.hands[0].Bet = amount
+	mgr.emit(EventBet, p.ID(), nil, amount)
+	return nil
+}
+
+// Deal deals two cards to each Player who has bet and to the house.
+// The house's second card is its face-down hole card.
+// If the house has a natural blackjack, the hand is settled immediately.
+func (mgr *BJGameManager) Deal() {
+	if !mgr.playing || mgr.dealt {
+		return
+	}
+	mgr.dealt = true
+	for _, p := range mgr.bettors() {
+		p.ReplaceHand(mgr.dealer.DealHand(2))
+	}
+	mgr.house = mgr.dealer.DealHand(2)
+	mgr.emit(EventDealt, NoSeat, mgr.house[:1], 0)
+
+	if (BJHand{Cards: mgr.house}).Blackjack() {
+		for _, p := range mgr.bettors() {
+			p.hands[0].Done = true
+		}
+	}
+	for _, p := range mgr.bettors() {
+		if p.hands[0].Blackjack() {
+			p.hands[0].Done = true
+		}
+	}
+	mgr.table.SetCurrSeat(0)
+	mgr.nextTurn()
+}
+
+// Play is not a Blackjack action; Players Hit, Stand, DoubleDown or Split instead.
+func (mgr *BJGameManager) Play(p Player, h Hand) error {
+	return errors.New("blackjack players hit, stand, double down or split")
+}
+
+// Hit deals one more card to the Player's current hand.
+// The hand is finished if it busts or reaches 21.
+func (mgr *BJGameManager) Hit(p Player) error {
+	bj, err := mgr.checkTurn(p)
+	if err != nil {
+		return err
+	}
+	hand := bj.activeHand()
+	hand.Cards = append(hand.Cards, mgr.dealer.DealHand(1)...)
+	mgr.emit(EventDrew, p.ID(), hand.Cards[len(hand.Cards)-1:], 0)
+	if total, _ := hand.Total(); total >= 21 {
+		hand.Done = true
+		if hand.Bust() {
+			mgr.emit(EventBusted, p.ID(), nil, total)
+		}
+		mgr.nextTurn()
+	}
+	return nil
+}
+
+// Stand finishes the Player's current hand.
+func (mgr *BJGameManager) Stand(p Player) error {
+	bj, err := mgr.checkTurn(p)
+	if err != nil {
+		return err
+	}
+	bj.activeHand().Done = true
+	total, _ := bj.activeHand().Total()
+	mgr.emit(EventStood, p.ID(), nil, total)
+	mgr.nextTurn()
+	return nil
+}
+
+// DoubleDown doubles the wager on the Player's current two-card hand,
+// deals it exactly one more card, and finishes it.
+func (mgr *BJGameManager) DoubleDown(p Player) error {
+	bj, err := mgr.checkTurn(p)
+	if err != nil {
+		return err
+	}
+	hand := bj.activeHand()
+	if len(hand.Cards) != 2 {
+		return errors.New("only a two-card hand can be doubled")
+	} else if hand.Split && !mgr.settings.DoubleAfterSplit {
+		return errors.New("cannot double after splitting")
+	} else if bj.chips < hand.Bet {
+		return errors.New("not enough chips")
+	}
+	bj.chips -= hand.Bet
+	hand.Bet *= 2
+	hand.Doubled = true
+	mgr.emit(EventDoubled, p.ID(), nil, hand.Bet)
+	hand.Cards = append(hand.Cards, mgr.dealer.DealHand(1)...)
+	mgr.emit(EventDrew, p.ID(), hand.Cards[2:], 0)
+	hand.Done = true
+	if total, _ := hand.Total(); hand.Bust() {
+		mgr.emit(EventBusted, p.ID(), nil, total)
+	}
+	mgr.nextTurn()
+	return nil
+}
+
+// Split splits the Player's current hand of two equal ranks into two hands,
+// wagering the same amount again on the second hand.
+// Each hand is dealt a second card. Split aces receive one card each and are finished.
+func (mgr *BJGameManager) Split(p Player) error {
+	bj, err := mgr.checkTurn(p)
+	if err != nil {
+		return err
+	}
+	hand := bj.activeHand()
+	if len(hand.Cards) != 2 || hand.Cards[0].rank != hand.Cards[1].rank {
+		return errors.New("only a pair can be split")
+	} else if len(bj.hands) >= mgr.settings.MaxSplitHands {
+		return errors.New("too many hands")
+	} else if bj.chips < hand.Bet {
+		return errors.New("not enough chips")
+	}
+	bj.chips -= hand.Bet
+	second := &BJHand{Cards: Hand{hand.Cards[1]}, Bet: hand.Bet, Split: true}
+	hand.Cards, hand.Split = Hand{hand.Cards[0]}, true
+	bj.hands = append(bj.hands[:bj.active+1], append([]*BJHand{second}, bj.hands[bj.active+1:]...)...)
+	mgr.emit(EventSplit, p.ID(), nil, hand.Bet)
+
+	for _, h := range []*BJHand{hand, second} {
+		h.Cards = append(h.Cards, mgr.dealer.DealHand(1)...)
+		if total, _ := h.Total(); h.Cards[0].rank == Ace || total == 21 {
+			h.Done = true
+		}
+	}
+	mgr.nextTurn()
+	return nil
+}
+
+// House returns the house's hand.
+// The hole card is hidden, and excluded, until the Players have finished their hands.
+func (mgr *BJGameManager) House() Hand {
+	if mgr.dealt && len(mgr.house) > 1 {
+		return Hand{mgr.house[0]}
+	}
+	return append(Hand{}, mgr.house...)
+}
+
+// nextTurn moves play to the next unfinished hand, first within the current Player's split hands
+// and then around the Table. Once every hand is finished, the house plays and bets are settled.
+func (mgr *BJGameManager) nextTurn() {
+	for seat := mgr.table.CurrSeat(); seat < mgr.table.Size(); seat++ {
+		p, _ := mgr.table.Player(seat)
+		if bj, ok := p.(*BJPlayer); ok && len(bj.hands) > 0 && bj.hands[0].Bet > 0 {
+			for i, h := range bj.hands {
+				if !h.Done {
+					bj.active = i
+					mgr.table.SetCurrSeat(seat)
+					return
+				}
+			}
+		}
+	}
+	mgr.settle()
+}
+
+// playHouse reveals the hole card and draws to the house's hand until it reaches 17 or more.
+// Depending on the settings, the house either hits or stands on a soft 17.
+func (mgr *BJGameManager) playHouse() {
+	mgr.emit(EventRevealed, NoSeat, mgr.house, 0)
+	if !mgr.needsHouse() {
+		return
+	}
+	for {
+		total, soft := handTotal(mgr.house)
+		if total > 17 || (total == 17 && !(soft && mgr.settings.HitSoft17)) {
+			break
+		}
+		mgr.house = append(mgr.house, mgr.dealer.DealHand(1)...)
+		mgr.emit(EventDrew, NoSeat, mgr.house[len(mgr.house)-1:], 0)
+	}
+}
+
+// needsHouse returns true if any Player holds a hand that the house must draw against.
+func (mgr *BJGameManager) needsHouse() bool {
+	for _, p := range mgr.bettors() {
+		for _, h := range p.hands {
+			if !h.Bust() && !h.Blackjack() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// settle plays the house's hand, pays out every Player and collects the cards for the next hand.
+func (mgr *BJGameManager) settle() {
+	mgr.playHouse()
+	house := BJHand{Cards: mgr.house}
+	houseTotal, _ := house.Total()
+
+	var used Hand
+	for _, p := range mgr.bettors() {
+		net := 0
+		for _, h := range p.hands {
+			payout := mgr.Payout(*h, house)
+			p.chips += h.Bet + payout
+			net += payout
+			used = append(used, h.Cards...)
+		}
+		mgr.emit(EventSettled, p.ID(), nil, net)
+		p.hands = nil
+		p.active = 0
+	}
+	used = append(used, mgr.house...)
+	mgr.dealer.HandleDiscard(used)
+	mgr.emit(EventHandOver, NoSeat, nil, houseTotal)
+	mgr.dealt = false
+}
+
+// Payout returns the chips won (or, if negative, lost) by a Player's hand against the house's hand.
+// A natural blackjack pays according to the settings, any other win pays even money,
+// and a push returns the wager.
+func (mgr *BJGameManager) Payout(h BJHand, house BJHand) int {
+	total, _ := h.Total()
+	houseTotal, _ := house.Total()
+	switch {
+	case h.Bust():
+		return -h.Bet
+	case h.Blackjack() && house.Blackjack():
+		return 0
+	case h.Blackjack():
+		return h.Bet * mgr.settings.BlackjackPays / mgr.settings.BlackjackPer
+	case house.Blackjack():
+		return -h.Bet
+	case house.Bust() || total > houseTotal:
+		return h.Bet
+	case total < houseTotal:
+		return -h.Bet
+	default:
+		return 0
+	}
+}
+
+// bettors returns the Players who have wagered on the current hand, in seating order.
+func (mgr *BJGameManager) bettors() []*BJPlayer {
+	var b []*BJPlayer
+	for _, p := range mgr.table.Players() {
+		if bj, ok := p.(*BJPlayer); ok && len(bj.hands) > 0 && bj.hands[0].Bet > 0 {
+			b = append(b, bj)
+		}
+	}
+	return b
+}
+
+// bjPlayer returns the Blackjack player seated in the Player's seat.
+func (mgr *BJGameManager) bjPlayer(p Player) (*BJPlayer, error) {
+	if !mgr.playing {
+		return nil, errors.New("no game in progress")
+	} else if seated, ok := mgr.table.Player(p.ID()); !ok {
+		return nil, errors.New("not seated at this table")
+	} else if bj, ok := seated.(*BJPlayer); ok {
+		return bj, nil
+	}
+	return nil, errors.New("not a blackjack player")
+}
+
+// checkTurn returns the Player's Blackjack seat if it is their turn to act on a hand.
+func (mgr *BJGameManager) checkTurn(p Player) (*BJPlayer, error) {
+	bj, err := mgr.bjPlayer(p)
+	if err != nil {
+		return nil, err
+	} else if !mgr.dealt {
+		return nil, errors.New("no hand in progress")
+	} else if mgr.table.CurrSeat() != p.ID() || bj.activeHand() == nil || bj.activeHand().Done {
+		return nil, errors.New("playing out of turn")
+	}
+	return bj, nil
+}
+
+// EndGame ends the game. Any hand in progress is abandoned and its wagers are returned.
+func (mgr *BJGameManager) EndGame() {
+	for _, p := range mgr.bettors() {
+		for _, h := range p.hands {
+			p.chips += h.Bet
+		}
+		p.hands = nil
+	}
+	mgr.dealt = false
+	mgr.playing = false
+	mgr.emit(EventGameEnded, NoSeat, nil, 0)
+}
+
+// InHand returns true while a hand is being played, between the deal and the settlement.
+func (mgr *BJGameManager) InHand() bool {
+	return mgr.dealt
+}
+
+// CurrPlayer returns the player who is currently taking their turn.
+func (mgr *BJGameManager) CurrPlayer() Player {
+	return mgr.table.CurrPlayer()
+}
+
+// Playing returns true while a game is in progress.
+func (mgr *BJGameManager) Playing() bool {
+	return mgr.playing
+}
+
+// Table returns the Table at which the game is being played.
+func (mgr *BJGameManager) Table() *Table {
+	return mgr.table
+}
+
+// Events returns the stream of Events emitted by the game.
+func (mgr *BJGameManager) Events() *EventStream {
+	return mgr.events
+}
+
+// SetEvents records the game's Events in the given stream rather than a new one,
+// so the game can be observed before it begins.
+func (mgr *BJGameManager) SetEvents(s *EventStream) {
+	mgr.events = s
+}
+
+// emit records an Event with the given count, e.g. a wager or a hand total.
+func (mgr *BJGameManager) emit(t EventType, seat int, cards Hand, count int) {
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: t, Seat: seat, Cards: cards, Count: count})
+}
@@ -0,0 +1,142 @@
+package cards
+
+import (
+	"shuffle/utils"
+	"testing"
+)
+
+type TotalResult struct {
+	cards Hand
+	total int
+	soft  bool
+}
+
+func TestHandTotal(t *testing.T) {
+	tests := map[string]TotalResult{
+		"hard":           {Hand{NewCard(Ten, Clubs), NewCard(Seven, Hearts)}, 17, false},
+		"soft":           {Hand{NewCard(Ace, Clubs), NewCard(Six, Hearts)}, 17, true},
+		"soft to hard":   {Hand{NewCard(Ace, Clubs), NewCard(Six, Hearts), NewCard(Nine, Spades)}, 16, false},
+		"two aces":       {Hand{NewCard(Ace, Clubs), NewCard(Ace, Hearts)}, 12, true},
+		"face cards":     {Hand{NewCard(King, Clubs), NewCard(Queen, Hearts)}, 20, false},
+		"blackjack":      {Hand{NewCard(Ace, Clubs), NewCard(Jack, Hearts)}, 21, true},
+		"bust":           {Hand{NewCard(King, Clubs), NewCard(Queen, Hearts), NewCard(Two, Hearts)}, 22, false},
+		"empty":          {Hand{}, 0, false},
+		"many low cards": {Hand{NewCard(Ace, Clubs), NewCard(Two, Clubs), NewCard(Three, Clubs), NewCard(Four, Clubs)}, 20, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			total, soft := handTotal(test.cards)
+			utils.Error(t, total, test.total, "total")
+			utils.Error(t, soft, test.soft, "soft")
+		})
+	}
+}
+
+type PayoutResult struct {
+	hand  BJHand
+	house Hand
+	want  int
+}
+
+func TestPayout(t *testing.T) {
+	twenty := Hand{NewCard(King, Clubs), NewCard(Queen, Clubs)}
+	nineteen := Hand{NewCard(King, Clubs), NewCard(Nine, Clubs)}
+	natural := Hand{NewCard(Ace, Clubs), NewCard(King, Clubs)}
+	bust := Hand{NewCard(King, Clubs), NewCard(Queen, Clubs), NewCard(Five, Clubs)}
+
+	tests := map[string]PayoutResult{
+		"win":               {BJHand{Cards: twenty, Bet: 10}, nineteen, 10},
+		"lose":              {BJHand{Cards: nineteen, Bet: 10}, twenty, -10},
+		"push":              {BJHand{Cards: twenty, Bet: 10}, twenty, 0},
+		"blackjack":         {BJHand{Cards: natural, Bet: 10}, twenty, 15},
+		"blackjack push":    {BJHand{Cards: natural, Bet: 10}, natural, 0},
+		"house blackjack":   {BJHand{Cards: twenty, Bet: 10}, natural, -10},
+		"player bust":       {BJHand{Cards: bust, Bet: 10}, bust, -10},
+		"house bust":        {BJHand{Cards: nineteen, Bet: 10}, bust, 10},
+		"split 21 is no BJ": {BJHand{Cards: natural, Bet: 10, Split: true}, twenty, 10},
+		"doubled win":       {BJHand{Cards: twenty, Bet: 20, Doubled: true}, nineteen, 20},
+	}
+
+	mgr := &BJGameManager{settings: BJDefaultSettings}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			utils.Error(t, mgr.Payout(test.hand, BJHand{Cards: test.house}), test.want)
+		})
+	}
+}
+
+// newTestBJGame starts a quiet game of Blackjack between the named players,
+// dealing from a Shoe stacked with the given cards.
+func newTestBJGame(settings *BJGameSettings, stacked Shoe, names ...string) (*BJGameManager, []*BJPlayer) {
+	mgr := new(BJGameManager)
+	players := make([]*BJPlayer, len(names))
+	for i, name := range names {
+		players[i] = NewBJPlayer(name)
+	}
+	mgr.StartGame(players, settings)
+	mgr.dealer = &dealer{draw: stacked, rand: NewRngAt(1)}
+	return mgr, players
+}
+
+func TestBlackjackRound(t *testing.T) {
+	stacked := Shoe{
+		NewCard(Eight, Clubs), NewCard(Eight, Hearts), // Alice
+		NewCard(Ten, Clubs), NewCard(Six, Hearts), // Bob
+		NewCard(Ten, Spades), NewCard(Seven, Spades), // house
+		NewCard(Three, Clubs), NewCard(Ten, Hearts), // Alice's split hands
+		NewCard(King, Diamonds), // Alice doubles her first hand
+		NewCard(Nine, Diamonds), // Bob hits
+	}
+	mgr, players := newTestBJGame(nil, stacked, "Alice", "Bob")
+	alice, bob := players[0], players[1]
+
+	utils.Fatal(t, mgr.Bet(alice, 10), nil)
+	utils.Fatal(t, mgr.Bet(bob, 10), nil)
+	mgr.Deal()
+	utils.Error(t, mgr.House(), Hand{NewCard(Ten, Spades)}, "visible house hand")
+	utils.Error(t, mgr.Stand(bob) != nil, true, "error playing out of turn")
+
+	utils.Fatal(t, mgr.Split(alice), nil)
+	utils.Error(t, alice.Hand(), Hand{NewCard(Eight, Clubs), NewCard(Three, Clubs)}, "first split hand")
+	utils.Fatal(t, mgr.DoubleDown(alice), nil)
+	utils.Error(t, alice.Hand(), Hand{NewCard(Eight, Hearts), NewCard(Ten, Hearts)}, "second split hand")
+	utils.Fatal(t, mgr.Stand(alice), nil)
+
+	utils.Error(t, mgr.CurrPlayer().ID(), bob.ID(), "current player")
+	utils.Fatal(t, mgr.Hit(bob), nil)
+	utils.Error(t, mgr.InHand(), false, "in hand after every player finished")
+
+	// Alice doubles 21 and stands on 18 against 17; Bob busts.
+	utils.Error(t, alice.Chips(), 100+20+10, "Alice's chips")
+	utils.Error(t, bob.Chips(), 100-10, "Bob's chips")
+	utils.Error(t, mgr.House(), Hand{NewCard(Ten, Spades), NewCard(Seven, Spades)}, "revealed house hand")
+}
+
+func TestHouseSoft17(t *testing.T) {
+	tests := map[string]bool{
+		"stands on soft 17": false,
+		"hits soft 17":      true,
+	}
+
+	for name, hit := range tests {
+		t.Run(name, func(t *testing.T) {
+			stacked := Shoe{
+				NewCard(Ten, Clubs), NewCard(Nine, Hearts),
+				NewCard(Ace, Spades), NewCard(Six, Spades),
+				NewCard(Two, Diamonds),
+			}
+			settings := *BJDefaultSettings
+			settings.HitSoft17 = hit
+			mgr, players := newTestBJGame(&settings, stacked, "Alice")
+			utils.Fatal(t, mgr.Bet(players[0], 10), nil)
+			mgr.Deal()
+			utils.Fatal(t, mgr.Stand(players[0]), nil)
+			want := 2
+			if hit {
+				want = 3
+			}
+			utils.Error(t, len(mgr.House()), want, "cards in house hand")
+		})
+	}
+}
@@ -0,0 +1,448 @@
+package cards
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The 99 bot protocol lets robots written in any language play 99, much as chess engines speak UCI.
+// The engine runs each bot as a separate process and talks to it over its standard input and output,
+// one line at a time. Cards are written as their rank and the first letter of their suit, e.g. "10H" or "QS".
+//
+// The engine opens with a handshake, which the bot answers with its name:
+//
+//	99bot 1
+//	ready <name>
+//
+// On each of the bot's turns, the engine sends its view of the game, from "view" to "go":
+//
+//	view
+//	settings cards 3 lives 3 max 99 reverse 4 ninetynine 9 minusten 10 zero K
+//	seat 2
+//	round 1
+//	count 45
+//	direction 1
+//	hand 2H 9C 10S
+//	lives 3 3 3 0
+//	hands 3 3 3 0
+//	discard 5D KH 7C
+//	go
+//
+// Lives and hand sizes are listed for every seat, and eliminated seats have no lives.
+// The bot answers with the card it plays:
+//
+//	play 9C
+//
+// When the game is over the engine sends "quit", and the bot should exit.
+// Bots may write "info <text>" lines at any time, which the engine ignores,
+// and should ignore any line they do not understand.
+const botProtocolVersion = 1
+
+// BotPrefix marks a strategy name as the command line of an external bot, e.g. "bot:./mybot -level 3".
+const BotPrefix = "bot:"
+
+// BotConfig describes how to run an external bot.
+type BotConfig struct {
+	Command   []string      // the program to run and its arguments
+	Timeout   time.Duration // the most time the bot may take over its handshake and each move
+	MaxFaults int           // the number of timeouts and illegal moves after which the bot is disqualified
+	Fallback  NNStrategy    // plays for the bot when it faults, and for the rest of the game once it is disqualified
+	Stderr    io.Writer     // receives the bot's standard error; discarded if nil
+}
+
+// BotDefaults are the limits placed on external bots, unless configured otherwise.
+var BotDefaults = BotConfig{
+	Timeout:   5 * time.Second,
+	MaxFaults: 3,
+}
+
+// errBotExited is returned when the bot's process closes its output.
+var errBotExited = errors.New("bot exited")
+
+// BotStrategy plays 99 by asking an external bot process for each move.
+// The bot is isolated from the game: if it crashes, times out or plays a card it does not hold,
+// the fallback strategy moves in its place, and a bot that faults too often is disqualified.
+type BotStrategy struct {
+	cfg    BotConfig
+	name   string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	lines  chan string
+	done   chan struct{}
+	stale  int // the number of replies owed for moves that timed out
+	faults int
+	dead   bool
+	once   sync.Once
+}
+
+// NewBotStrategy starts an external bot and completes the handshake with it.
+func NewBotStrategy(ctx context.Context, cfg BotConfig) (*BotStrategy, error) {
+	if len(cfg.Command) == 0 {
+		return nil, errors.New("no bot command given")
+	}
+	if cfg.Fallback == nil {
+		cfg.Fallback = NewCautiousStrategy()
+	}
+	s := &BotStrategy{
+		cfg:   cfg,
+		name:  BotPrefix + strings.Join(cfg.Command, " "),
+		cmd:   exec.Command(cfg.Command[0], cfg.Command[1:]...),
+		lines: make(chan string),
+		done:  make(chan struct{}),
+	}
+	s.cmd.Stderr = cfg.Stderr
+	stdout, err := s.cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to bot")
+	}
+	if s.stdin, err = s.cmd.StdinPipe(); err != nil {
+		return nil, errors.Wrap(err, "could not connect to bot")
+	}
+	if err := s.cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "could not start bot %q", cfg.Command[0])
+	}
+	go s.read(stdout)
+
+	if err := s.send(fmt.Sprintf("99bot %d", botProtocolVersion)); err != nil {
+		s.Close()
+		return nil, err
+	}
+	reply, err := s.receive(ctx, "ready")
+	if err != nil {
+		s.Close()
+		return nil, errors.Wrap(err, "bot failed the handshake")
+	}
+	if name := strings.TrimSpace(strings.TrimPrefix(reply, "ready")); name != "" {
+		s.name = name
+	}
+	return s, nil
+}
+
+// newBotFromName starts the external bot named by a strategy name with the BotPrefix.
+func newBotFromName(name string) (NNStrategy, error) {
+	cfg := BotDefaults
+	cfg.Command = strings.Fields(strings.TrimPrefix(name, BotPrefix))
+	cfg.Stderr = os.Stderr
+	s, err := NewBotStrategy(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.name = name
+	return s, nil
+}
+
+// read forwards each line the bot writes, other than info lines, until its output closes.
+func (s *BotStrategy) read(r io.Reader) {
+	defer close(s.lines)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "info") {
+			continue
+		}
+		select {
+		case s.lines <- line:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// send writes a line to the bot.
+func (s *BotStrategy) send(line string) error {
+	if _, err := io.WriteString(s.stdin, line+"\n"); err != nil {
+		return errors.Wrap(err, "could not write to bot")
+	}
+	return nil
+}
+
+// receive waits for the next line from the bot that starts with the given command,
+// first skipping the replies to any moves that timed out.
+func (s *BotStrategy) receive(ctx context.Context, command string) (string, error) {
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+	for {
+		select {
+		case line, ok := <-s.lines:
+			if !ok {
+				return "", errBotExited
+			}
+			if fields := strings.Fields(line); fields[0] != command {
+				continue
+			} else if s.stale > 0 && command == "play" {
+				s.stale--
+				continue
+			}
+			return line, nil
+		case <-ctx.Done():
+			if command == "play" {
+				s.stale++
+			}
+			return "", errors.Wrap(ctx.Err(), "bot did not reply in time")
+		}
+	}
+}
+
+// Name returns the name the bot gave in its handshake, or its strategy name if it was started by name.
+func (s *BotStrategy) Name() string {
+	return s.name
+}
+
+// Faults returns the number of moves the bot has failed to make.
+func (s *BotStrategy) Faults() int {
+	return s.faults
+}
+
+// Disqualified returns true if the bot has crashed or faulted too many times to keep playing.
+func (s *BotStrategy) Disqualified() bool {
+	return s.dead
+}
+
+// Choose sends the view to the bot and returns the index of the card it plays.
+// If the bot fails to make a legal move in time, the fallback strategy chooses instead.
+func (s *BotStrategy) Choose(ctx context.Context, v NNView) (int, error) {
+	if s.dead {
+		return s.cfg.Fallback.Choose(ctx, v)
+	}
+	i, err := s.ask(ctx, v)
+	if err != nil {
+		s.faults++
+		if s.cfg.MaxFaults > 0 && s.faults >= s.cfg.MaxFaults {
+			s.Close()
+		}
+		return s.cfg.Fallback.Choose(ctx, v)
+	}
+	return i, nil
+}
+
+// ask sends the view to the bot and reads back a card from the player's hand.
+func (s *BotStrategy) ask(ctx context.Context, v NNView) (int, error) {
+	var b strings.Builder
+	WriteBotView(&b, v)
+	if err := s.send(strings.TrimSuffix(b.String(), "\n")); err != nil {
+		s.Close()
+		return 0, err
+	}
+	reply, err := s.receive(ctx, "play")
+	if err != nil {
+		if err == errBotExited {
+			s.Close()
+		}
+		return 0, err
+	}
+	c, err := ParseCard(strings.TrimSpace(strings.TrimPrefix(reply, "play")))
+	if err != nil {
+		return 0, errors.Wrap(err, "bot played an illegal move")
+	}
+	for i, held := range v.Hand {
+		if held == c {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("bot played %v, which is not in its hand", c)
+}
+
+// Close tells the bot to quit and releases its process, killing it if it does not exit promptly.
+func (s *BotStrategy) Close() error {
+	s.once.Do(func() {
+		s.dead = true
+		s.send("quit")
+		s.stdin.Close()
+		close(s.done)
+		exited := make(chan struct{})
+		go func() {
+			s.cmd.Wait()
+			close(exited)
+		}()
+		select {
+		case <-exited:
+		case <-time.After(time.Second):
+			s.cmd.Process.Kill()
+			<-exited
+		}
+	})
+	return nil
+}
+
+// botCard writes a card as its rank and the first letter of its suit, e.g. "10H".
+func botCard(c Card) string {
+	return string(c.rank) + string(c.suit)[:1]
+}
+
+// botCards writes a list of cards separated by spaces.
+func botCards(cards []Card) string {
+	text := make([]string, len(cards))
+	for i, c := range cards {
+		text[i] = botCard(c)
+	}
+	return strings.Join(text, " ")
+}
+
+// botInts writes a list of numbers separated by spaces.
+func botInts(n []int) string {
+	text := make([]string, len(n))
+	for i, x := range n {
+		text[i] = strconv.Itoa(x)
+	}
+	return strings.Join(text, " ")
+}
+
+// WriteBotView writes a player's view in the 99 bot protocol, from "view" to "go".
+func WriteBotView(w io.Writer, v NNView) error {
+	wild := v.Settings.WildCards
+	lives := make([]int, len(v.Lives))
+	for seat := range lives {
+		if seat < len(v.Active) && v.Active[seat] {
+			lives[seat] = v.Lives[seat]
+		}
+	}
+	_, err := fmt.Fprintf(w, "view\n"+
+		"settings cards %d lives %d max %d reverse %s ninetynine %s minusten %s zero %s\n"+
+		"seat %d\nround %d\ncount %d\ndirection %d\nhand %s\nlives %s\nhands %s\ndiscard %s\ngo\n",
+		v.Settings.CardsPerPlayer, v.Settings.LivesPerPlayer, v.Settings.MaxCount,
+		wild.Reverse, wild.NinetyNine, wild.MinusTen, wild.Zero,
+		v.Seat, v.Round, v.Count, v.Direction,
+		botCards(v.Hand), botInts(lives), botInts(v.HandSizes), botCards(v.Discard))
+	return err
+}
+
+// readBotView reads a player's view in the 99 bot protocol, after the "view" line up to and including "go".
+func readBotView(sc *bufio.Scanner) (NNView, error) {
+	var v NNView
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		key, args := fields[0], fields[1:]
+		var err error
+		switch key {
+		case "go":
+			v.Current = v.Seat
+			v.Active = make([]bool, len(v.Lives))
+			for seat, lives := range v.Lives {
+				v.Active[seat] = lives > 0
+			}
+			return v, nil
+		case "settings":
+			err = readBotSettings(&v.Settings, args)
+		case "seat":
+			v.Seat, err = readBotInt(args)
+		case "round":
+			v.Round, err = readBotInt(args)
+		case "count":
+			v.Count, err = readBotInt(args)
+		case "direction":
+			v.Direction, err = readBotInt(args)
+		case "hand":
+			v.Hand, err = ParseHand(strings.Join(args, " "))
+		case "lives":
+			v.Lives, err = readBotInts(args)
+		case "hands":
+			v.HandSizes, err = readBotInts(args)
+		case "discard":
+			v.Discard, err = ParseShoe(strings.Join(args, " "))
+		}
+		if err != nil {
+			return v, errors.Wrapf(err, "invalid %s", key)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return v, err
+	}
+	return v, io.ErrUnexpectedEOF
+}
+
+// readBotInt reads a single number.
+func readBotInt(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, errors.Errorf("want 1 number, got %d", len(args))
+	}
+	return strconv.Atoi(args[0])
+}
+
+// readBotInts reads a list of numbers.
+func readBotInts(args []string) ([]int, error) {
+	n := make([]int, len(args))
+	for i, arg := range args {
+		x, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = x
+	}
+	return n, nil
+}
+
+// readBotSettings reads the rules of the game from pairs of names and values.
+func readBotSettings(set *NNGameSettings, args []string) error {
+	for i := 0; i+1 < len(args); i += 2 {
+		var err error
+		switch value := args[i+1]; args[i] {
+		case "cards":
+			set.CardsPerPlayer, err = strconv.Atoi(value)
+		case "lives":
+			set.LivesPerPlayer, err = strconv.Atoi(value)
+		case "max":
+			set.MaxCount, err = strconv.Atoi(value)
+		case "reverse":
+			set.WildCards.Reverse, err = ParseRank(value)
+		case "ninetynine":
+			set.WildCards.NinetyNine, err = ParseRank(value)
+		case "minusten":
+			set.WildCards.MinusTen, err = ParseRank(value)
+		case "zero":
+			set.WildCards.Zero, err = ParseRank(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServeBot speaks the 99 bot protocol as a bot, reading from r and writing to w,
+// and plays every move with the given strategy. It returns once it is told to quit or r is exhausted.
+// It is the reference implementation of a bot.
+func ServeBot(ctx context.Context, r io.Reader, w io.Writer, s NNStrategy) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "99bot":
+			fmt.Fprintf(w, "ready %s\n", s.Name())
+		case "view":
+			v, err := readBotView(sc)
+			if err != nil {
+				return err
+			}
+			i, err := s.Choose(ctx, v)
+			if err != nil || i < 0 || i >= len(v.Hand) {
+				fmt.Fprintf(w, "info no move: %v\n", err)
+				i = 0
+			}
+			if len(v.Hand) > 0 {
+				fmt.Fprintf(w, "play %s\n", botCard(v.Hand[i]))
+			}
+		case "quit":
+			return nil
+		}
+	}
+	return sc.Err()
+}
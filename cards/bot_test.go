@@ -0,0 +1,143 @@
+package cards
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"shuffle/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBotHelperProcess is not a real test. It is run as an external bot by the other tests,
+// misbehaving in the way named by the BOT_HELPER environment variable.
+func TestBotHelperProcess(t *testing.T) {
+	mode := os.Getenv("BOT_HELPER")
+	if mode == "" {
+		return
+	}
+	defer os.Exit(0)
+	if mode == "cautious" {
+		ServeBot(context.Background(), os.Stdin, os.Stdout, NewCautiousStrategy())
+		return
+	}
+
+	sc := bufio.NewScanner(os.Stdin)
+	for sc.Scan() {
+		switch strings.Fields(sc.Text() + " x")[0] {
+		case "99bot":
+			if mode == "silent" {
+				time.Sleep(time.Minute)
+			}
+			fmt.Println("info helper starting")
+			fmt.Println("ready helper-" + mode)
+		case "go":
+			switch mode {
+			case "illegal":
+				fmt.Println("play AS")
+			case "garbage":
+				fmt.Println("play the best card")
+			case "slow":
+				time.Sleep(200 * time.Millisecond)
+				fmt.Println("play 10H")
+			case "crash":
+				os.Exit(3)
+			}
+		case "quit":
+			return
+		}
+	}
+}
+
+// helperBot starts the test binary as an external bot in the given mode.
+func helperBot(t *testing.T, mode string, cfg BotConfig) *BotStrategy {
+	t.Helper()
+	os.Setenv("BOT_HELPER", mode)
+	defer os.Unsetenv("BOT_HELPER")
+	cfg.Command = []string{os.Args[0], "-test.run=TestBotHelperProcess"}
+	s, err := NewBotStrategy(context.Background(), cfg)
+	utils.Fatal(t, err, nil)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+var botTestView = NNView{
+	Seat:      1,
+	Round:     2,
+	Hand:      Hand{NewCard(Ten, Hearts), NewCard(Nine, Clubs), NewCard(Two, Spades)},
+	Count:     90,
+	Direction: -1,
+	Lives:     []int{2, 3, 0},
+	HandSizes: []int{3, 3, 0},
+	Active:    []bool{true, true, false},
+	Discard:   Shoe{NewCard(King, Diamonds)},
+	Settings:  *NNDefaultSettings,
+}
+
+func TestBotView(t *testing.T) {
+	var b bytes.Buffer
+	utils.Fatal(t, WriteBotView(&b, botTestView), nil)
+	utils.Error(t, strings.Contains(b.String(), "hand 10H 9C 2S\n"), true, "hand written as ASCII")
+
+	sc := bufio.NewScanner(&b)
+	sc.Scan()
+	utils.Error(t, sc.Text(), "view")
+	v, err := readBotView(sc)
+	utils.Fatal(t, err, nil)
+	want := botTestView
+	want.Current = want.Seat
+	utils.Error(t, v, want)
+}
+
+func TestBotStrategy(t *testing.T) {
+	s := helperBot(t, "cautious", BotDefaults)
+	utils.Error(t, s.Name(), "cautious")
+	i, err := s.Choose(context.Background(), botTestView)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, botTestView.Hand[i], NewCard(Two, Spades))
+	utils.Error(t, s.Faults(), 0)
+}
+
+func TestBotFaults(t *testing.T) {
+	cfg := BotDefaults
+	cfg.Timeout = 50 * time.Millisecond
+	cfg.MaxFaults = 2
+	cfg.Fallback = NewAggressiveStrategy()
+
+	for _, mode := range []string{"illegal", "garbage", "slow", "crash"} {
+		s := helperBot(t, mode, cfg)
+		i, err := s.Choose(context.Background(), botTestView)
+		utils.Fatal(t, err, nil, mode)
+		utils.Error(t, botTestView.Hand[i], NewCard(Nine, Clubs), mode+" falls back to aggressive")
+		utils.Error(t, s.Faults(), 1, mode+" faults")
+
+		s.Choose(context.Background(), botTestView)
+		utils.Error(t, s.Disqualified(), true, mode+" disqualified")
+		i, err = s.Choose(context.Background(), botTestView)
+		utils.Fatal(t, err, nil, mode)
+		utils.Error(t, botTestView.Hand[i], NewCard(Nine, Clubs), mode+" plays on after disqualification")
+	}
+}
+
+func TestBotHandshakeTimeout(t *testing.T) {
+	os.Setenv("BOT_HELPER", "silent")
+	defer os.Unsetenv("BOT_HELPER")
+	cfg := BotDefaults
+	cfg.Timeout = 50 * time.Millisecond
+	cfg.Command = []string{os.Args[0], "-test.run=TestBotHelperProcess"}
+	_, err := NewBotStrategy(context.Background(), cfg)
+	utils.Error(t, err != nil, true, "error for a silent bot")
+}
+
+func TestSimulateBots(t *testing.T) {
+	os.Setenv("BOT_HELPER", "cautious")
+	defer os.Unsetenv("BOT_HELPER")
+	bot := BotPrefix + os.Args[0] + " -test.run=TestBotHelperProcess"
+	report, err := Simulate(context.Background(), SimConfig{Games: 3, Workers: 1, Seed: 2021, Strategies: []string{bot, "cautious"}})
+	utils.Fatal(t, err, nil)
+	utils.Error(t, report.Games, 3, "games played")
+	utils.Error(t, report.Unfinished, 0, "unfinished games")
+}
@@ -2,10 +2,10 @@ package cards
 
 import (
 	"fmt"
+	"os"
+	"shuffle/render"
 	"shuffle/utils"
 	"strings"
-
-	"github.com/fatih/color"
 )
 
 // Rank is a model class for playing card ranks.
@@ -68,6 +68,16 @@ func (s Suit) String() string {
 	}
 }
 
+// Valid returns true if the suit is one of the four Suits.
+func (s Suit) Valid() bool {
+	for _, suit := range Suits {
+		if s == suit {
+			return true
+		}
+	}
+	return false
+}
+
 // Card is a model class for playing cards.
 // cards are uniquely identified by their rank and suit.
 type Card struct {
@@ -94,17 +104,14 @@ func (c Card) String() string {
 }
 
 // colourString augments a Card's string representation by colouring it.
-// Cards are coloured based on their suit:
-// Diamonds and Hearts are red, Clubs and Spades are white.
+// Cards are coloured based on their suit, in the palette set by SetCardPalette:
+// by default, Diamonds and Hearts are red, Clubs and Spades are white.
+// If cards are drawn by name, colourString is the Card's long name instead.
 // colourString is used primarily for command line applications, including debugging.
 func (c Card) colourString() string {
 	switch c.suit {
-	case Clubs, Spades:
-		white := color.New(color.FgWhite).SprintfFunc()
-		return white(c.String())
-	case Diamonds, Hearts:
-		red := color.New(color.FgRed).SprintFunc()
-		return red(c.String())
+	case Clubs, Spades, Diamonds, Hearts:
+		return cardRenderer.Inline(c.face())
 	default:
 		return fmt.Sprintln("no card found")
 	}
@@ -133,21 +140,63 @@ func NewShoe(numDecks int) Shoe {
 	return s
 }
 
-// String converts a Card slice to a compact, coloured representation.
+// cardRenderer draws Hands and Shoes. It draws them compactly unless SetCardStyle is called,
+// so that they can be read back with ParseShoe, and without colour if NO_COLOR is set.
+var cardRenderer = render.Renderer{Style: render.Compact, Palette: render.DefaultPalette()}
+
+// SetCardStyle changes how Hands and Shoes are drawn on the command line,
+// wrapping them to the width of the terminal.
+func SetCardStyle(style render.Style) {
+	cardRenderer.Style = style
+	cardRenderer.Width, _ = terminalSize(os.Stdout)
+}
+
+// SetCardPalette changes the colours cards are drawn in on the command line.
+func SetCardPalette(palette render.Palette) {
+	cardRenderer.Palette = palette
+}
+
+// face converts a Card to what can be seen of it. The zero Card is a card seen from behind.
+func (c Card) face() render.Face {
+	return render.Face{
+		Rank: c.rank.String(),
+		Suit: c.suit.String(),
+		Red:  c.suit == Diamonds || c.suit == Hearts,
+		Name: c.longString(),
+		Down: c == Card{},
+	}
+}
+
+// faces converts a Card slice to what can be seen of the cards.
+func faces(cards []Card) []render.Face {
+	f := make([]render.Face, len(cards))
+	for i, c := range cards {
+		f[i] = c.face()
+	}
+	return f
+}
+
+// String converts a Card slice to a coloured representation, in the style set by SetCardStyle.
 func String(cards []Card) string {
-	str := make([]string, len(cards))
-	for i, card := range cards {
-		str[i] = card.colourString()
+	return cardRenderer.Render(faces(cards))
+}
+
+// inlineString converts a Card slice to a coloured representation that fits in a sentence:
+// by name if cards are drawn by name, and compactly otherwise.
+func inlineString(cards []Card) string {
+	r := cardRenderer
+	if r.Style != render.Names {
+		r.Style = render.Compact
 	}
-	return fmt.Sprintf("[%s]", strings.Join(str, ", "))
+	return r.Render(faces(cards))
 }
 
-// String converts a Hand to a compact, coloured representation.
+// String converts a Hand to a coloured representation, in the style set by SetCardStyle.
 func (h Hand) String() string {
 	return String(h)
 }
 
-// String converts a Shoe to a compact, coloured representation.
+// String converts a Shoe to a coloured representation, in the style set by SetCardStyle.
 func (s Shoe) String() string {
 	return String(s)
 }
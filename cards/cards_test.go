@@ -1,8 +1,12 @@
 package cards
 
 import (
+	"shuffle/render"
 	"shuffle/utils"
+	"strings"
 	"testing"
+
+	"github.com/fatih/color"
 )
 
 const (
@@ -85,3 +89,24 @@ func testShoe(t *testing.T, s Shoe, test ShoeResult) {
 		utils.Error(t, got, cardsPerRank, rank.String())
 	}
 }
+
+func TestCardStyle(t *testing.T) {
+	defer func(r render.Renderer, noColor bool) { cardRenderer, color.NoColor = r, noColor }(cardRenderer, color.NoColor)
+	color.NoColor = true
+	h := Hand{NewCard(Queen, Spades), NewCard(Ten, Hearts), {}}
+
+	utils.Error(t, h.String(), "[Q♠, 10♥, ??]", "compact by default")
+	parsed, err := ParseShoe(Shoe(h[:2]).String())
+	utils.Error(t, err, nil)
+	utils.Error(t, parsed, Shoe(h[:2]), "compact cards can be read back")
+
+	cardRenderer = render.Renderer{Style: render.Boxes, Width: 16}
+	lines := strings.Split(h.String(), "\n")
+	utils.Error(t, len(lines), 2*render.BoxHeight, "boxes wrapped to the width")
+	utils.Error(t, lines[2], "|  ♠  | |  ♥  |")
+	utils.Error(t, lines[7], "|/////|", "face down")
+	utils.Error(t, inlineString(h), "[Q♠, 10♥, ??]", "inline cards stay compact")
+
+	cardRenderer = render.Renderer{Style: render.Glyphs}
+	utils.Error(t, h.String(), "🂭 🂺 🂠")
+}
@@ -0,0 +1,208 @@
+package cards
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// ChatConfig holds the limits on what players at a table may say to each other.
+type ChatConfig struct {
+	MaxLength int           // the most characters in a message, or 0 for no limit
+	Burst     int           // the most messages and emotes a player may send in a row, or 0 for no limit
+	Interval  time.Duration // how long a player who has sent a burst of messages waits for each one more they may send
+	Filter    bool          // whether profanity is masked with asterisks
+}
+
+// ChatDefaults are the limits on chat at the tables of a Lobby, unless configured otherwise.
+var ChatDefaults = ChatConfig{
+	MaxLength: 200,
+	Burst:     3,
+	Interval:  2 * time.Second,
+	Filter:    true,
+}
+
+// Emotes maps the name of each quick emote to what it says.
+var Emotes = map[string]string{
+	"nines":   "Watch out for those 9's!",
+	"gg":      "Good game!",
+	"luck":    "Good luck, everyone!",
+	"close":   "That was close!",
+	"oops":    "Oops!",
+	"wow":     "Wow!",
+	"thanks":  "Thanks!",
+	"shuffle": "Who's gonna shuffle?",
+}
+
+// EmoteNames lists the names of the quick emotes, in alphabetical order.
+func EmoteNames() []string {
+	names := make([]string, 0, len(Emotes))
+	for name := range Emotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profanity holds the words masked by the profanity filter, in lowercase, in each of the Languages.
+var profanity = map[string]bool{
+	"arse": true, "ass": true, "asshole": true, "bastard": true, "bitch": true, "bollocks": true, "crap": true,
+	"damn": true, "dick": true, "fuck": true, "fucked": true, "fucker": true, "fucking": true, "piss": true,
+	"pissed": true, "shit": true, "shitty": true, "wanker": true,
+	"cazzo": true, "coglione": true, "merda": true, "stronzo": true, "stronza": true, "vaffanculo": true,
+	"connard": true, "connasse": true, "merde": true, "putain": true, "salaud": true, "salope": true,
+}
+
+// censor masks every word of profanity in the text with asterisks, one for each letter.
+func censor(text string) string {
+	var b strings.Builder
+	var word []rune
+	flush := func() {
+		if profanity[strings.ToLower(string(word))] {
+			b.WriteString(strings.Repeat("*", len(word)))
+		} else {
+			b.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
+
+// chatLimiter limits how quickly a player may chat, allowing them a burst of messages
+// and then one more each interval.
+type chatLimiter struct {
+	allowance float64
+	last      time.Time
+}
+
+// allow returns true if a player may send a message now, and counts it against their allowance.
+func (l *chatLimiter) allow(cfg ChatConfig, now time.Time) bool {
+	if cfg.Burst <= 0 {
+		return true
+	}
+	if l.last.IsZero() {
+		l.allowance = float64(cfg.Burst)
+	} else if cfg.Interval > 0 {
+		l.allowance += float64(now.Sub(l.last)) / float64(cfg.Interval)
+		if l.allowance > float64(cfg.Burst) {
+			l.allowance = float64(cfg.Burst)
+		}
+	}
+	l.last = now
+	if l.allowance < 1 {
+		return false
+	}
+	l.allowance--
+	return true
+}
+
+// Chat says something to everyone at the table, as an Event.
+// It returns an error if the message is empty or too long, the person is chatting too quickly,
+// or the host has muted them. Control characters are stripped, and profanity is masked if the lobby filters it.
+func (s *Seat) Chat(text string) error {
+	text = strings.TrimSpace(stripControl(text))
+	cfg := s.table.lobby.cfg.Chat
+	if text == "" {
+		return errors.New("say something")
+	} else if cfg.MaxLength > 0 && len([]rune(text)) > cfg.MaxLength {
+		return errors.Errorf("messages have at most %d characters", cfg.MaxLength)
+	}
+	if cfg.Filter {
+		text = censor(text)
+	}
+	return s.say(EventChat, text)
+}
+
+// stripControl removes control characters, such as terminal escape sequences, from text typed by a person.
+func stripControl(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// Emote says one of the quick emotes to everyone at the table, as an Event.
+// It returns an error if there is no emote with that name, the person is chatting too quickly,
+// or the host has muted them.
+func (s *Seat) Emote(name string) error {
+	text, ok := Emotes[strings.ToLower(name)]
+	if !ok {
+		return errors.Errorf("no emote %q; try %s", name, strings.Join(EmoteNames(), ", "))
+	}
+	return s.say(EventEmote, text)
+}
+
+// say emits a chat Event from the person in the seat, unless they are muted or chatting too quickly.
+func (s *Seat) say(typ EventType, text string) (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if s.replaced {
+			err = errors.New("you no longer have a seat at the table")
+			return
+		} else if s.muted {
+			err = errors.New("the host has muted you")
+			return
+		} else if !s.limiter.allow(s.table.lobby.cfg.Chat, time.Now()) {
+			err = errors.New("you are chatting too quickly")
+			return
+		}
+		s.table.emit(mgr, typ, s, text)
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// Mute stops, or with on false lets again, the person with the given name chatting at the table.
+// Only the host may mute people, and they cannot mute themselves.
+func (s *Seat) Mute(name string, on bool) (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		t := s.table
+		target := t.seated(name)
+		if !t.isHost(s) {
+			err = errNotHost
+			return
+		} else if target == nil {
+			err = errors.Errorf("nobody called %s is at the table", name)
+			return
+		} else if target == s {
+			err = errors.New("the host cannot mute themselves")
+			return
+		} else if target.muted == on {
+			return
+		}
+		target.muted = on
+		typ := EventUnmuted
+		if on {
+			typ = EventMuted
+		}
+		t.emit(mgr, typ, target, "")
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// emit records an Event about a person at the table, which may come before the game begins and they are seated.
+func (t *LobbyTable) emit(mgr *NNGameManager, typ EventType, s *Seat, text string) {
+	seat := NoSeat
+	if mgr.Playing() {
+		seat = s.player.ID()
+	}
+	mgr.Events().Emit(Event{Game: t.opts.Settings.Game(), Type: typ, Seat: seat, Count: mgr.Count(), Name: s.Name(), Text: text})
+}
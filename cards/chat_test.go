@@ -0,0 +1,118 @@
+package cards
+
+import (
+	"bytes"
+	"fmt"
+	"shuffle/utils"
+	"testing"
+	"time"
+)
+
+func TestCensor(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Watch out for those 9's!", "Watch out for those 9's!"},
+		{"Oh SHIT, a nine", "Oh ****, a nine"},
+		{"damn-it, crap!", "****-it, ****!"},
+		{"Classic assassin move", "Classic assassin move"},
+		{"Che merda", "Che *****"},
+		{"putain de roi", "****** de roi"},
+	}
+	for _, test := range tests {
+		utils.Error(t, censor(test.text), test.want, test.text)
+	}
+}
+
+func TestChatLimiter(t *testing.T) {
+	cfg := ChatConfig{Burst: 2, Interval: time.Second}
+	var l chatLimiter
+	start := time.Now()
+	tests := []struct {
+		after time.Duration
+		allow bool
+	}{
+		{0, true},
+		{0, true},
+		{0, false},
+		{500 * time.Millisecond, false},
+		{time.Second, true},
+		{time.Second, false},
+		{10 * time.Second, true},
+		{10 * time.Second, true},
+		{10 * time.Second, false},
+	}
+	for i, test := range tests {
+		utils.Error(t, l.allow(cfg, start.Add(test.after)), test.allow, fmt.Sprint(i))
+	}
+	utils.Error(t, new(chatLimiter).allow(ChatConfig{}, start), true, "no limit")
+}
+
+func TestChat(t *testing.T) {
+	var log bytes.Buffer
+	lobby := NewLobby(LobbyConfig{
+		Chat: ChatConfig{MaxLength: 20, Burst: 2, Interval: time.Hour, Filter: true},
+		Log:  NewEventLog(&log),
+	})
+	defer lobby.Close()
+	table, err := lobby.CreateTable(TableOptions{Seats: 3})
+	utils.Fatal(t, err, nil)
+	alice, err := lobby.Join(table.ID(), "Alice")
+	utils.Fatal(t, err, nil)
+	bob, err := lobby.Join(table.ID(), "Bob")
+	utils.Fatal(t, err, nil)
+	utils.Error(t, table.Info().Host, "Alice", "the first to sit down looks after the table")
+
+	utils.Error(t, fmt.Sprint(alice.Chat("  ")), "say something")
+	utils.Error(t, fmt.Sprint(alice.Chat("this message is much too long")), "messages have at most 20 characters")
+	utils.Error(t, bob.Chat("oh crap"), nil)
+	utils.Error(t, bob.Emote("NINES"), nil)
+	utils.Error(t, bob.Emote("dance") != nil, true, "unknown emote")
+	utils.Error(t, fmt.Sprint(bob.Chat("so many 9s")), "you are chatting too quickly")
+
+	utils.Error(t, fmt.Sprint(bob.Mute("Alice", true)), "only the host can do that")
+	utils.Error(t, fmt.Sprint(alice.Mute("alice", true)), "the host cannot mute themselves")
+	utils.Error(t, fmt.Sprint(alice.Mute("Charlie", true)), "nobody called Charlie is at the table")
+	utils.Error(t, alice.Mute("bob", true), nil)
+	utils.Error(t, fmt.Sprint(bob.Emote("gg")), "the host has muted you")
+	utils.Error(t, alice.Mute("bob", false), nil)
+
+	events, err := ReadEventLog(&log)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, len(events), 4)
+	want := []Event{
+		{Type: EventChat, Name: "Bob", Text: "oh ****"},
+		{Type: EventEmote, Name: "Bob", Text: "Watch out for those 9's!"},
+		{Type: EventMuted, Name: "Bob"},
+		{Type: EventUnmuted, Name: "Bob"},
+	}
+	for i, e := range events {
+		utils.Error(t, e.Table, table.ID(), "chat is logged with the table")
+		utils.Error(t, e.Seat, NoSeat, "before the game begins")
+		utils.Error(t, e.Type, want[i].Type, e.Text)
+		utils.Error(t, e.Name, want[i].Name, e.Text)
+		utils.Error(t, e.Text, want[i].Text, e.Text)
+	}
+	line, _ := describeNNEvent(nil, events[1])
+	utils.Error(t, line, "Bob: Watch out for those 9's!")
+
+	utils.Error(t, alice.Leave(), nil)
+	utils.Error(t, table.Info().Host, "Bob", "the host passes on when they leave")
+}
+
+func TestChatControlCharacters(t *testing.T) {
+	utils.Error(t, stripControl("\x1b[2Jhi\tthere\a\r\n"), "[2Jhithere")
+
+	lobby := NewLobby(LobbyConfig{})
+	defer lobby.Close()
+	table, err := lobby.CreateTable(TableOptions{Seats: 3})
+	utils.Fatal(t, err, nil)
+	_, err = lobby.Join(table.ID(), "\x1b\a")
+	utils.Error(t, fmt.Sprint(err), "names need at least one printable character")
+	eve, err := lobby.Join(table.ID(), "\x1b[31mEve\x1b[0m")
+	utils.Fatal(t, err, nil)
+	utils.Error(t, eve.Name(), "[31mEve[0m", "control characters are stripped from names")
+	utils.Fatal(t, eve.Chat("\x1b[2Jhello\x07"), nil)
+	e := waitFor(t, table, EventChat)
+	utils.Error(t, e.Text, "[2Jhello", "control characters are stripped from chat")
+}
@@ -0,0 +1,321 @@
+package cards
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// undoSelection is returned by promptCard when the Player asks to take back the latest card played.
+const undoSelection = -1
+
+// promptCard asks the current Player to select a card from their Hand on the command line.
+// The status function is called before each attempt, to remind the Player of the state of the game.
+// If canUndo is true, the Player may instead enter "u" to take back the latest card played.
+// It returns the 1-based position of the selected card, undoSelection, or 0 if the input is closed.
+func promptCard(p Player, status func(), canUndo bool) int {
+	say("%v, it's your turn. Select a card from 1-%v", p.Name(), len(p.Hand()))
+	if canUndo {
+		say("Or enter u to take back the last card played.")
+	}
+	for {
+		status()
+		var input string
+		_, err := fmt.Scan(&input)
+		if err == io.EOF {
+			return 0
+		}
+		if canUndo && strings.EqualFold(input, "u") {
+			return undoSelection
+		}
+		if card, err := strconv.Atoi(input); err == nil && card > 0 && card <= len(p.Hand()) {
+			return card
+		}
+		say("Please make a valid selection.")
+	}
+}
+
+// showHints prints the consequences of playing each card in a hand of 99.
+func showHints(hints []CardHint) {
+	for _, h := range hints {
+		fmt.Println(describeHint(h))
+	}
+}
+
+// describeHint describes the consequences of playing a card in a hand of 99.
+func describeHint(h CardHint) string {
+	if h.Safe {
+		return translate("  %d) %v -> count %d, next player forced to bust %.1f%%", h.Index+1, h.Card.colourString(), h.Count, 100*h.BustsNext)
+	}
+	return translate("  %d) %v -> count %d, busts!", h.Index+1, h.Card.colourString(), h.Count)
+}
+
+// announce prints the Events of a game of 99 on the command line.
+func (mgr *NNGameManager) announce(e Event) {
+	switch e.Type {
+	case EventGameCreated:
+		say("Welcome to 99!")
+	case EventBusted:
+		p, _ := mgr.table.Player(e.Seat)
+		say("%d points busts %d! %v loses!", e.Count, mgr.settings.MaxCount, p.Name())
+	case EventUndone:
+		p, _ := mgr.table.Player(e.Seat)
+		say("%v takes back %v. The count is %d again.", p.Name(), e.Cards[0].colourString(), e.Count)
+	case EventGameEnded:
+		say("Thanks for playing!")
+		mgr.revealTable()
+	}
+}
+
+// narrate prints the Events of a game of 99 on the command line as plain sentences,
+// which screen readers can read aloud.
+func (mgr *NNGameManager) narrate(e Event) {
+	switch e.Type {
+	case EventGameCreated:
+		say("Welcome to 99!")
+	case EventGameEnded:
+		say("Thanks for playing!")
+	default:
+		if line, ok := describeNNEvent(tableNames(mgr.table), e); ok {
+			fmt.Println(sentence(line))
+		}
+	}
+}
+
+// describeTurn describes the state of a game of 99 to the Player whose turn it is, in plain sentences.
+func (mgr *NNGameManager) describeTurn(p Player) string {
+	direction := translate("clockwise")
+	if mgr.table.Direction() < 0 {
+		direction = translate("counterclockwise")
+	}
+	return translate("It's %v's turn. The count is %d and play goes %v. You hold %v.",
+		p.Name(), mgr.count, direction, listCards(p.Hand())) + " " +
+		sentence(translate("%s has %d lives left", p.Name(), mgr.Lives(p.ID())))
+}
+
+// listCards lists the long names of cards in a sentence, e.g. "Queen of Spades, Two of Hearts and Nine of Clubs".
+func listCards(cards []Card) string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = c.longString()
+	}
+	return listNames(names)
+}
+
+// listNames lists names in a sentence, e.g. "Alice, Bob and Charlie".
+func listNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return translate("%v and %v", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// sentence ends a line with a full stop, unless it already ends with punctuation.
+func sentence(line string) string {
+	if strings.HasSuffix(line, ".") || strings.HasSuffix(line, "!") || strings.HasSuffix(line, "?") {
+		return line
+	}
+	return line + "."
+}
+
+// NewGame begins a command line game of Crazy Eights with a number of players.
+// The game follows the default house rules.
+func (mgr *CEGameManager) NewGame(players []*CEPlayer) {
+	mgr.NewGameWithRobots(players, 0)
+}
+
+// NewGameWithRobots begins a command line game of Crazy Eights with a number of human players and robot players.
+// The game follows the default house rules.
+func (mgr *CEGameManager) NewGameWithRobots(players []*CEPlayer, robots int) {
+	if mgr.events == nil {
+		mgr.events = NewEventStream()
+	}
+	mgr.events.Subscribe(mgr.announce)
+	mgr.StartGameWithRobots(players, robots, nil)
+
+	for mgr.playing {
+		player := mgr.CurrPlayer()
+		if ce, ok := player.(*CEPlayer); ok && ce.Robot() {
+			if err := mgr.PlayTurn(context.Background()); err != nil {
+				handlePlayError(err)
+			}
+			continue
+		}
+		mgr.revealTable() // TODO: temporary, remove after debugging
+		if len(mgr.PlayableCards(player.Hand())) == 0 {
+			if _, err := mgr.Draw(player); err != nil {
+				handlePlayError(err)
+			}
+			continue
+		}
+		card := promptCard(player, func() {
+			top, suit := mgr.Top()
+			say("Top: %v (%v to follow)", top.colourString(), suit.localString())
+		}, false)
+		if card == 0 {
+			mgr.EndGame()
+			break
+		}
+		c, _ := selectCardAt(player, card-1)
+		declared := c.suit
+		if c.rank == mgr.settings.Wild {
+			declared = promptSuit()
+		}
+		if err := mgr.PlayWild(player, c, declared); err != nil {
+			handlePlayError(err)
+		}
+	}
+}
+
+// promptSuit asks the current Player to declare a suit on the command line.
+func promptSuit() Suit {
+	choices := make([]string, len(Suits))
+	for i, suit := range Suits {
+		choices[i] = fmt.Sprintf("%d) %v", i+1, suit.localString())
+	}
+	say("Declare a suit: %v", strings.Join(choices, " "))
+	var suit int
+	for {
+		_, err := fmt.Scanf("%d", &suit)
+		if err == io.EOF {
+			return Suits[0]
+		} else if err != nil || suit <= 0 || suit > len(Suits) {
+			say("Please make a valid selection.")
+		} else {
+			return Suits[suit-1]
+		}
+	}
+}
+
+// announce prints the Events of a game of Crazy Eights on the command line.
+func (mgr *CEGameManager) announce(e Event) {
+	p, _ := mgr.table.Player(e.Seat)
+	switch e.Type {
+	case EventGameCreated:
+		say("Welcome to Crazy Eights!")
+	case EventDeclared:
+		say("%v declares %v!", p.Name(), e.Suit.localString())
+	case EventDrew:
+		say("%v draws a card.", p.Name())
+	case EventPassed:
+		say("%v cannot play and passes.", p.Name())
+	case EventWon:
+		say("%v wins!", p.Name())
+	case EventGameEnded:
+		say("Thanks for playing!")
+		scores := mgr.Scores()
+		for _, q := range mgr.table.Players() {
+			say("%v\t(p%v): \t%d points", q.Name(), q.ID(), scores[q.ID()])
+		}
+		mgr.revealTable()
+	}
+}
+
+// NewGame begins a command line game of Blackjack with a number of players.
+// The game follows the default house rules, and continues hand after hand until nobody bets.
+func (mgr *BJGameManager) NewGame(players []*BJPlayer) {
+	if mgr.events == nil {
+		mgr.events = NewEventStream()
+	}
+	mgr.events.Subscribe(mgr.announce)
+	mgr.StartGame(players, nil)
+
+	for mgr.playing {
+		for _, p := range players {
+			if p.chips < mgr.settings.MinBet {
+				continue
+			}
+			for {
+				say("%v, you have %d chips. Place a bet (0 to sit out)", p.Name(), p.chips)
+				var bet int
+				if _, err := fmt.Scanf("%d", &bet); err == io.EOF {
+					mgr.EndGame()
+					return
+				} else if err == nil && bet == 0 {
+					break
+				} else if err = mgr.Bet(p, bet); err == nil {
+					break
+				} else {
+					handlePlayError(err)
+				}
+			}
+		}
+		if len(mgr.bettors()) == 0 {
+			mgr.EndGame()
+			break
+		}
+
+		mgr.Deal()
+		for mgr.dealt {
+			p := mgr.CurrPlayer()
+			total, soft := handTotal(p.Hand())
+			say("House shows %v", mgr.House())
+			say("%v, you hold %v (%v). (h)it, (s)tand, (d)ouble or s(p)lit?", p.Name(), inlineString(p.Hand()), describeTotal(total, soft))
+			var action string
+			if _, err := fmt.Scanln(&action); err == io.EOF {
+				mgr.EndGame()
+				return
+			}
+			var err error
+			switch action {
+			case "h":
+				err = mgr.Hit(p)
+			case "s":
+				err = mgr.Stand(p)
+			case "d":
+				err = mgr.DoubleDown(p)
+			case "p":
+				err = mgr.Split(p)
+			default:
+				say("Please make a valid selection.")
+			}
+			if err != nil {
+				handlePlayError(err)
+			}
+		}
+	}
+}
+
+// describeTotal describes a Blackjack total as soft or hard.
+func describeTotal(total int, soft bool) string {
+	if soft {
+		return translate("soft %d", total)
+	}
+	return translate("hard %d", total)
+}
+
+// announce prints the Events of a game of Blackjack on the command line.
+func (mgr *BJGameManager) announce(e Event) {
+	p, _ := mgr.table.Player(e.Seat)
+	switch e.Type {
+	case EventGameCreated:
+		say("Welcome to Blackjack!")
+	case EventDrew:
+		if p != nil {
+			say("%v draws %v.", p.Name(), e.Cards)
+		} else {
+			say("The house draws %v.", e.Cards)
+		}
+	case EventBusted:
+		say("%d busts! %v loses the hand.", e.Count, p.Name())
+	case EventRevealed:
+		total, soft := handTotal(e.Cards)
+		say("The house reveals %v (%v).", e.Cards, describeTotal(total, soft))
+	case EventSettled:
+		switch {
+		case e.Count > 0:
+			say("%v wins %d chips!", p.Name(), e.Count)
+		case e.Count < 0:
+			say("%v loses %d chips.", p.Name(), -e.Count)
+		default:
+			say("%v pushes.", p.Name())
+		}
+	case EventGameEnded:
+		say("Thanks for playing!")
+		for _, q := range mgr.table.Players() {
+			say("%v\t(p%v): \t%d chips", q.Name(), q.ID(), q.(*BJPlayer).chips)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package cards
+
+import (
+	"math/bits"
+
+	"github.com/pkg/errors"
+)
+
+// CardsPerDeck is the number of cards in a standard deck.
+const CardsPerDeck = len(Suits) * len(Ranks)
+
+// rankIndices and suitIndices map Ranks and Suits to their positions in Ranks and Suits.
+var (
+	rankIndices = make(map[Rank]uint8, len(Ranks))
+	suitIndices = make(map[Suit]uint8, len(Suits))
+)
+
+func init() {
+	for i, r := range Ranks {
+		rankIndices[r] = uint8(i)
+	}
+	for i, s := range Suits {
+		suitIndices[s] = uint8(i)
+	}
+}
+
+// Index returns the card's position in an unshuffled standard deck, from 0 to 51.
+// Cards are ordered by suit, then by rank, matching the order of NewShoe.
+// It returns an error if the card's rank or suit is invalid.
+func (c Card) Index() (uint8, error) {
+	r, okRank := rankIndices[c.rank]
+	s, okSuit := suitIndices[c.suit]
+	if !okRank || !okSuit {
+		return 0, errors.Errorf("cannot index invalid card %v", c)
+	}
+	return s*uint8(len(Ranks)) + r, nil
+}
+
+// CardAt returns the card at position i of an unshuffled standard deck.
+// It is the inverse of Card.Index. It returns an error if i is not a position in the deck.
+func CardAt(i uint8) (Card, error) {
+	if int(i) >= CardsPerDeck {
+		return Card{}, errors.Errorf("card index %d out of range", i)
+	}
+	return NewCard(Ranks[i%uint8(len(Ranks))], Suits[i/uint8(len(Ranks))]), nil
+}
+
+// CompactCard packs a card into 16 bits: its index into a standard deck in the low byte,
+// and the deck of a multi-deck Shoe it belongs to in the high byte.
+type CompactCard uint16
+
+// Compact encodes a card from the given deck of a Shoe.
+func Compact(c Card, deck int) (CompactCard, error) {
+	i, err := c.Index()
+	if err != nil {
+		return 0, err
+	} else if deck < 0 || deck > 0xff {
+		return 0, errors.Errorf("deck %d out of range", deck)
+	}
+	return CompactCard(deck)<<8 | CompactCard(i), nil
+}
+
+// Index returns the card's position in an unshuffled standard deck.
+func (cc CompactCard) Index() uint8 {
+	return uint8(cc)
+}
+
+// Deck returns the deck of a multi-deck Shoe the card belongs to.
+func (cc CompactCard) Deck() uint8 {
+	return uint8(cc >> 8)
+}
+
+// Card decodes the compact card.
+// It returns an error if its index is not a position in a standard deck.
+func (cc CompactCard) Card() (Card, error) {
+	return CardAt(cc.Index())
+}
+
+// CompactShoe is a Shoe of compact cards.
+type CompactShoe []CompactCard
+
+// Compact encodes every card in the Shoe, preserving order.
+// Copies of the same card are assigned to successive decks in the order they appear.
+func (s Shoe) Compact() (CompactShoe, error) {
+	var copies [CardsPerDeck]int
+	cs := make(CompactShoe, len(s))
+	for i, c := range s {
+		idx, err := c.Index()
+		if err != nil {
+			return nil, err
+		}
+		if cs[i], err = Compact(c, copies[idx]); err != nil {
+			return nil, err
+		}
+		copies[idx]++
+	}
+	return cs, nil
+}
+
+// Shoe decodes every card in the CompactShoe, preserving order.
+// It returns an error if any card cannot be decoded.
+func (cs CompactShoe) Shoe() (Shoe, error) {
+	s := make(Shoe, len(cs))
+	for i, cc := range cs {
+		c, err := cc.Card()
+		if err != nil {
+			return nil, err
+		}
+		s[i] = c
+	}
+	return s, nil
+}
+
+// CardSet is a multiset of cards, designed for constant-time membership tests and removals.
+// A bit mask records which cards are present, and a count records how many copies of each
+// are present, to support hands drawn from multi-deck Shoes.
+// The zero value is an empty CardSet.
+type CardSet struct {
+	mask   uint64
+	counts [CardsPerDeck]uint8
+}
+
+// NewCardSet creates a CardSet containing the given cards.
+func NewCardSet(cards []Card) (*CardSet, error) {
+	s := new(CardSet)
+	for _, c := range cards {
+		if err := s.Add(c); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds a copy of the card to the set.
+func (s *CardSet) Add(c Card) error {
+	i, err := c.Index()
+	if err != nil {
+		return err
+	} else if s.counts[i] == 0xff {
+		return errors.Errorf("too many copies of %v", c)
+	}
+	s.counts[i]++
+	s.mask |= 1 << i
+	return nil
+}
+
+// Remove removes a copy of the card from the set.
+// It returns false if the card is not in the set.
+func (s *CardSet) Remove(c Card) bool {
+	i, err := c.Index()
+	if err != nil || s.counts[i] == 0 {
+		return false
+	}
+	s.counts[i]--
+	if s.counts[i] == 0 {
+		s.mask &^= 1 << i
+	}
+	return true
+}
+
+// Contains returns true if at least one copy of the card is in the set.
+func (s *CardSet) Contains(c Card) bool {
+	i, err := c.Index()
+	return err == nil && s.mask&(1<<i) != 0
+}
+
+// Count returns the number of copies of the card in the set.
+func (s *CardSet) Count(c Card) int {
+	if i, err := c.Index(); err == nil {
+		return int(s.counts[i])
+	}
+	return 0
+}
+
+// Len returns the number of cards in the set, counting every copy.
+func (s *CardSet) Len() int {
+	n := 0
+	for m := s.mask; m != 0; m &= m - 1 {
+		n += int(s.counts[bits.TrailingZeros64(m)])
+	}
+	return n
+}
+
+// Mask returns a bit mask of the cards in the set, where bit i is set if the card at Index i is present.
+func (s *CardSet) Mask() uint64 {
+	return s.mask
+}
+
+// Hand returns the cards in the set as a Hand, in the order of an unshuffled standard deck.
+func (s *CardSet) Hand() Hand {
+	h := make(Hand, 0, s.Len())
+	for m := s.mask; m != 0; m &= m - 1 {
+		i := uint8(bits.TrailingZeros64(m))
+		c, _ := CardAt(i)
+		for n := uint8(0); n < s.counts[i]; n++ {
+			h = append(h, c)
+		}
+	}
+	return h
+}
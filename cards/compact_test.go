@@ -0,0 +1,119 @@
+package cards
+
+import (
+	"shuffle/utils"
+	"testing"
+)
+
+func TestCardIndex(t *testing.T) {
+	for i, c := range NewShoe(1) {
+		got, err := c.Index()
+		utils.Fatal(t, err, nil)
+		utils.Error(t, int(got), i, c.String())
+		at, err := CardAt(got)
+		utils.Fatal(t, err, nil)
+		utils.Error(t, at, c)
+	}
+	_, err := CardAt(uint8(CardsPerDeck))
+	utils.Error(t, err != nil, true, "error for an index past the end of the deck")
+
+	_, err = NewCard("14", Hearts).Index()
+	utils.Error(t, err != nil, true, "error indexing an invalid card")
+}
+
+func TestCompactShoe(t *testing.T) {
+	shoe := NewShoe(3)
+	NewRngAt(2021).Shuffle(shoe)
+
+	cs, err := shoe.Compact()
+	utils.Fatal(t, err, nil)
+	decoded, err := cs.Shoe()
+	utils.Fatal(t, err, nil)
+	utils.Error(t, decoded, shoe, "after a round trip")
+
+	decks := make(map[CompactCard]bool)
+	for _, cc := range cs {
+		utils.Error(t, decks[cc], false, "for a duplicate compact card")
+		decks[cc] = true
+		utils.Error(t, cc.Deck() < 3, true, "for a deck id in range")
+	}
+
+	_, err = Compact(NewCard(Ace, Spades), 256)
+	utils.Error(t, err != nil, true, "error for an out of range deck")
+	_, err = CompactShoe{cs[0], CompactCard(1<<8 | 52)}.Shoe()
+	utils.Error(t, err != nil, true, "error decoding an invalid compact card")
+}
+
+func TestCardSet(t *testing.T) {
+	jack := NewCard(Jack, Spades)
+	set, err := NewCardSet(Hand{jack, NewCard(Two, Hearts), jack})
+	utils.Fatal(t, err, nil)
+
+	utils.Error(t, set.Len(), 3, "cards in set")
+	utils.Error(t, set.Contains(jack), true, "contains jack")
+	utils.Error(t, set.Count(jack), 2, "copies of jack")
+	utils.Error(t, set.Contains(NewCard(Two, Spades)), false, "contains absent card")
+	utils.Error(t, set.Hand(), Hand{NewCard(Two, Hearts), jack, jack})
+
+	utils.Error(t, set.Remove(jack), true, "removed first jack")
+	utils.Error(t, set.Contains(jack), true, "contains jack after removing one copy")
+	utils.Error(t, set.Remove(jack), true, "removed second jack")
+	utils.Error(t, set.Contains(jack), false, "contains jack after removing both copies")
+	utils.Error(t, set.Remove(jack), false, "removed absent jack")
+	utils.Error(t, set.Len(), 1, "cards in set")
+
+	_, err = NewCardSet(Hand{NewCard("14", Hearts)})
+	utils.Error(t, err != nil, true, "error adding an invalid card")
+
+	var empty CardSet
+	utils.Error(t, empty.Len(), 0, "cards in zero value")
+	utils.Error(t, empty.Mask(), uint64(0), "mask of zero value")
+}
+
+// benchmarkHand is a large hand, as held late in a long game, to compare lookups against.
+var benchmarkHand = Hand(NewShoe(1)[:40])
+
+func BenchmarkHandContains(b *testing.B) {
+	target := benchmarkHand[len(benchmarkHand)-1]
+	for n := 0; n < b.N; n++ {
+		for _, c := range benchmarkHand {
+			if c == target {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkCardSetContains(b *testing.B) {
+	set, _ := NewCardSet(benchmarkHand)
+	target := benchmarkHand[len(benchmarkHand)-1]
+	for n := 0; n < b.N; n++ {
+		set.Contains(target)
+	}
+}
+
+func BenchmarkHandRemoveAndReplace(b *testing.B) {
+	p := NewNNPlayer("bench")
+	p.ReplaceHand(append(Hand{}, benchmarkHand...))
+	target := benchmarkHand[len(benchmarkHand)-1]
+	for n := 0; n < b.N; n++ {
+		p.removeCard(target)
+		p.AcceptCards(Hand{target})
+	}
+}
+
+func BenchmarkCardSetRemoveAndReplace(b *testing.B) {
+	set, _ := NewCardSet(benchmarkHand)
+	target := benchmarkHand[len(benchmarkHand)-1]
+	for n := 0; n < b.N; n++ {
+		set.Remove(target)
+		set.Add(target)
+	}
+}
+
+func BenchmarkShoeCompact(b *testing.B) {
+	shoe := NewShoe(6)
+	for n := 0; n < b.N; n++ {
+		shoe.Compact()
+	}
+}
@@ -0,0 +1,495 @@
+package cards
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// CEGameSettings holds the relevant settings for a game of Crazy Eights.
+type CEGameSettings struct {
+	CardsPerPlayer int
+	Wild           Rank
+	WildPoints     int
+}
+
+// CEDefaultSettings are the "house rules" for a game of Crazy Eights.
+var CEDefaultSettings = &CEGameSettings{
+	CardsPerPlayer: 5,
+	Wild:           Eight,
+	WildPoints:     50,
+}
+
+// Game returns the name of the game these Rules are for.
+func (set *CEGameSettings) Game() string {
+	return "Crazy Eights"
+}
+
+// HandSize returns the number of cards dealt to each Player.
+func (set *CEGameSettings) HandSize() int {
+	return set.CardsPerPlayer
+}
+
+// Decks returns the number of decks to use in the Shoe for the given number of Players.
+// Enough decks are used that no more than half the Shoe is dealt out at the start of the game.
+func (set *CEGameSettings) Decks(numPlayers int) int {
+	dealt := 2 * numPlayers * set.CardsPerPlayer
+	if decks := (dealt + CardsPerDeck - 1) / CardsPerDeck; decks > 1 {
+		return decks
+	}
+	return 1
+}
+
+// Points returns the penalty for holding a card at the end of the game.
+// Wild cards are worth the most, face cards are worth 10, aces are worth 1,
+// and every other card is worth its face value.
+func (set *CEGameSettings) Points(c Card) int {
+	switch c.rank {
+	case set.Wild:
+		return set.WildPoints
+	case Ace:
+		return 1
+	case Jack, Queen, King:
+		return 10
+	default:
+		v, _ := strconv.Atoi(string(c.rank))
+		return v
+	}
+}
+
+// CEPlayer is an implementation of Player designed to play Crazy Eights.
+// It plays a single card per turn, declaring a suit whenever it plays a wild card.
+// Robot players choose their cards with a CEStrategy; human players have none.
+type CEPlayer struct {
+	player
+	strategy CEStrategy
+}
+
+// NewCEPlayer creates a Crazy Eights player with the given name.
+func NewCEPlayer(name string) *CEPlayer {
+	p := new(CEPlayer)
+	p.name = name
+	return p
+}
+
+// NewCERobot creates a Crazy Eights robot player with the given name, which plays according to the strategy.
+func NewCERobot(name string, s CEStrategy) *CEPlayer {
+	p := NewCEPlayer(name)
+	p.strategy = s
+	return p
+}
+
+// Robot returns true if the player's cards are chosen by a strategy rather than a person.
+func (p *CEPlayer) Robot() bool {
+	return p.strategy != nil
+}
+
+// Strategy returns the strategy a robot player uses to choose cards, or nil for a human player.
+func (p *CEPlayer) Strategy() CEStrategy {
+	return p.strategy
+}
+
+// PlayWild plays a wild card and declares the suit the next Player must follow.
+func (p *CEPlayer) PlayWild(c Card, declared Suit) error {
+	mgr, ok := p.mgr.(*CEGameManager)
+	if !ok {
+		return errors.New("not seated at a Crazy Eights table")
+	}
+	return mgr.PlayWild(p, c, declared)
+}
+
+// StartGameWithRobots begins a new game of Crazy Eights between people and robots,
+// which are seated after the people and shed their costliest cards first.
+func (mgr *CEGameManager) StartGameWithRobots(humans []*CEPlayer, robots int, settings *CEGameSettings) {
+	players := append([]*CEPlayer{}, humans...)
+	for i := 0; i < robots; i++ {
+		players = append(players, NewCERobot(fmt.Sprintf("Robot %d", i+1), NewSheddingStrategy()))
+	}
+	mgr.StartGame(players, settings)
+}
+
+// CEGameManager is an implementation of GameManager that plays Crazy Eights.
+// Players take turns matching the rank or suit of the top of the discard pile.
+// Wild cards match anything and let the Player who played them declare the suit to follow.
+// The first Player to empty their Hand wins, and everyone else scores the cards left in their Hand.
+type CEGameManager struct {
+	settings *CEGameSettings
+	table    *Table
+	dealer   *dealer
+	events   *EventStream
+	rng      Randomizer
+	playing  bool
+	declared Suit
+	winner   int
+	passes   int
+}
+
+// StartGame initializes and begins a new game of Crazy Eights.
+func (mgr *CEGameManager) StartGame(players []*CEPlayer, settings *CEGameSettings) {
+	if settings != nil {
+		mgr.settings = settings
+	} else {
+		mgr.settings = CEDefaultSettings
+	}
+	if mgr.events == nil {
+		mgr.events = NewEventStream()
+	}
+	mgr.table = NewTable()
+	for _, p := range players {
+		mgr.table.Sit(p, mgr)
+	}
+	mgr.winner = NoSeat
+	mgr.passes = 0
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventGameCreated, Seat: NoSeat, Names: tableNames(mgr.table)})
+	mgr.Deal()
+	mgr.playing = true
+}
+
+// SetRandomizer installs the Randomizer used to shuffle the Shoe.
+// By default, the Shoe is shuffled by a securely seeded Randomizer.
+func (mgr *CEGameManager) SetRandomizer(rng Randomizer) {
+	mgr.rng = rng
+}
+
+// Deal initializes the Dealer with an appropriately-sized shoe and deals cards to each player.
+// It also deals one card face up to start the discard pile, whose top card is never reshuffled into the draw pile.
+func (mgr *CEGameManager) Deal() {
+	rng := mgr.rng
+	if rng == nil {
+		rng = NewRng()
+	}
+	mgr.dealer = dealTable(mgr.settings, mgr.table, rng)
+	mgr.dealer.keepTop = true
+	mgr.table.SetCurrSeat(0)
+	h := mgr.dealer.DealHand(1)
+	mgr.discard(h[0])
+	mgr.emit(EventDealt, NoSeat, h)
+}
+
+// Top returns the card on top of the discard pile and the suit that must be followed.
+// The suit differs from the card's own suit only when a wild card was played.
+func (mgr *CEGameManager) Top() (Card, Suit) {
+	top, _ := mgr.dealer.TopDiscard()
+	return top, mgr.declared
+}
+
+// discard places a card on top of the discard pile, and follows its suit.
+func (mgr *CEGameManager) discard(c Card) {
+	mgr.dealer.HandleDiscard(Hand{c})
+	mgr.declared = c.suit
+}
+
+// Playable returns true if the card may be played on top of the discard pile.
+func (mgr *CEGameManager) Playable(c Card) bool {
+	top, suit := mgr.Top()
+	return c.rank == mgr.settings.Wild || c.suit == suit || c.rank == top.rank
+}
+
+// PlayableCards returns the cards in a Hand that may be played on top of the discard pile.
+func (mgr *CEGameManager) PlayableCards(h Hand) Hand {
+	var playable Hand
+	for _, c := range h {
+		if mgr.Playable(c) {
+			playable = append(playable, c)
+		}
+	}
+	return playable
+}
+
+// Play validates a player's move, discards their card, and advances play to the next player.
+// A wild card played this way declares its own suit.
+func (mgr *CEGameManager) Play(p Player, h Hand) error {
+	if len(h) != 1 {
+		return errors.New("play exactly one card")
+	}
+	return mgr.PlayWild(p, h[0], h[0].suit)
+}
+
+// PlayWild validates a player's move, discards their card, and advances play to the next player.
+// If the card is wild, the declared suit must be followed by the next player.
+func (mgr *CEGameManager) PlayWild(p Player, c Card, declared Suit) error {
+	if err := mgr.checkTurn(p); err != nil {
+		return err
+	} else if !mgr.Playable(c) {
+		return errors.New("card does not match the discard pile")
+	} else if c.rank == mgr.settings.Wild && !declared.Valid() {
+		return errors.New("invalid suit declared")
+	} else if seated, _ := mgr.table.Player(p.ID()); !seated.removeCard(c) {
+		return errors.New("cheating")
+	}
+
+	mgr.discard(c)
+	mgr.passes = 0
+	mgr.emit(EventCardPlayed, p.ID(), Hand{c})
+	if c.rank == mgr.settings.Wild {
+		mgr.declared = declared
+		mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventDeclared, Seat: p.ID(), Suit: declared})
+	}
+
+	if len(p.Hand()) == 0 {
+		mgr.winner = p.ID()
+		mgr.emit(EventWon, p.ID(), nil)
+		mgr.EndGame()
+		return nil
+	}
+	mgr.table.Advance()
+	return nil
+}
+
+// Draw deals cards to a player who cannot play until a playable card appears.
+// If the draw and discard piles run out first, the player passes instead.
+// It returns the cards drawn.
+func (mgr *CEGameManager) Draw(p Player) (Hand, error) {
+	if err := mgr.checkTurn(p); err != nil {
+		return nil, err
+	} else if len(mgr.PlayableCards(p.Hand())) > 0 {
+		return nil, errors.New("a playable card is already in hand")
+	}
+
+	seated, _ := mgr.table.Player(p.ID())
+	var drawn Hand
+	for {
+		h := mgr.dealer.DealHand(1)
+		if len(h) == 0 {
+			break
+		}
+		seated.AcceptCards(h)
+		drawn = append(drawn, h...)
+		mgr.emit(EventDrew, p.ID(), nil)
+		if mgr.Playable(h[0]) {
+			return drawn, nil
+		}
+	}
+	mgr.emit(EventPassed, p.ID(), nil)
+	if mgr.passes++; mgr.passes >= mgr.table.Size() {
+		mgr.block()
+		return drawn, nil
+	}
+	mgr.table.Advance()
+	return drawn, nil
+}
+
+// block ends a game in which nobody can play, awarding it to the Player with the lowest score.
+func (mgr *CEGameManager) block() {
+	scores := mgr.Scores()
+	for id := range mgr.table.Players() {
+		if mgr.winner == NoSeat || scores[id] < scores[mgr.winner] {
+			mgr.winner = id
+		}
+	}
+	mgr.emit(EventWon, mgr.winner, nil)
+	mgr.EndGame()
+}
+
+// checkTurn returns an error if it is not the player's turn.
+func (mgr *CEGameManager) checkTurn(p Player) error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if mgr.table.CurrSeat() != p.ID() {
+		return errors.New("playing out of turn")
+	}
+	return nil
+}
+
+// ScoreCard returns the penalty for holding a card at the end of the game, as Points does.
+func (mgr *CEGameManager) ScoreCard(c Card) int {
+	return mgr.settings.Points(c)
+}
+
+// Scores returns the penalty each Player scores for the cards left in their Hand, by seat.
+func (mgr *CEGameManager) Scores() map[int]int {
+	scores := make(map[int]int)
+	for _, p := range mgr.table.Players() {
+		for _, c := range p.Hand() {
+			scores[p.ID()] += mgr.ScoreCard(c)
+		}
+	}
+	return scores
+}
+
+// Winner returns the Player who emptied their Hand.
+// It returns false if nobody has won yet.
+func (mgr *CEGameManager) Winner() (Player, bool) {
+	return mgr.table.Player(mgr.winner)
+}
+
+// EndGame ends the game.
+func (mgr *CEGameManager) EndGame() {
+	mgr.playing = false
+	mgr.emit(EventGameEnded, NoSeat, nil)
+}
+
+// CurrPlayer returns the player who is currently taking their turn.
+func (mgr *CEGameManager) CurrPlayer() Player {
+	return mgr.table.CurrPlayer()
+}
+
+// Playing returns true while a game is in progress.
+func (mgr *CEGameManager) Playing() bool {
+	return mgr.playing
+}
+
+// Table returns the Table at which the game is being played.
+func (mgr *CEGameManager) Table() *Table {
+	return mgr.table
+}
+
+// Events returns the stream of Events emitted by the game.
+func (mgr *CEGameManager) Events() *EventStream {
+	return mgr.events
+}
+
+// SetEvents records the game's Events in the given stream rather than a new one,
+// so the game can be observed before it begins.
+func (mgr *CEGameManager) SetEvents(s *EventStream) {
+	mgr.events = s
+}
+
+// emit records an Event, using the number of cards left in the player's Hand as the count.
+func (mgr *CEGameManager) emit(t EventType, seat int, cards Hand) {
+	count := 0
+	if p, ok := mgr.table.Player(seat); ok {
+		count = len(p.Hand())
+	}
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: t, Seat: seat, Cards: cards, Count: count})
+}
+
+// PlayTurn plays the current player's turn, if they are a robot. A robot with no playable card draws until it has one,
+// or passes; otherwise its strategy chooses the card to play and the suit to declare.
+// If the strategy fails to choose a playable card, the first playable card in the robot's hand is played.
+// It returns an error if the current player is not a robot.
+func (mgr *CEGameManager) PlayTurn(ctx context.Context) error {
+	p, ok := mgr.CurrPlayer().(*CEPlayer)
+	if !ok || !p.Robot() {
+		return errors.New("the current player is not a robot")
+	}
+	if len(mgr.PlayableCards(p.Hand())) == 0 {
+		if _, err := mgr.Draw(p); err != nil {
+			return err
+		} else if !mgr.playing || mgr.table.CurrSeat() != p.ID() {
+			return nil
+		}
+	}
+	v := mgr.View(p.ID())
+	i, declared, err := p.strategy.Choose(ctx, v)
+	if err != nil || i < 0 || i >= len(v.Hand) || !v.Playable(v.Hand[i]) {
+		i = firstPlayable(v)
+		declared = v.Hand[i].suit
+	}
+	return mgr.PlayWild(p, v.Hand[i], declared)
+}
+
+// CEView is what a Crazy Eights player is allowed to know about the game.
+type CEView struct {
+	Seat      int
+	Hand      Hand
+	Top       Card  // the card on top of the discard pile
+	Suit      Suit  // the suit that must be followed
+	Current   int   // the seat of the player whose turn it is
+	HandSizes []int // the number of cards held by each seat
+	Settings  CEGameSettings
+}
+
+// View returns what the player in the given seat is allowed to know about the game.
+// The view is a copy, so changing it has no effect on the game.
+func (mgr *CEGameManager) View(seat int) CEView {
+	top, suit := mgr.Top()
+	v := CEView{
+		Seat:     seat,
+		Top:      top,
+		Suit:     suit,
+		Current:  mgr.table.CurrSeat(),
+		Settings: *mgr.settings,
+	}
+	if p, ok := mgr.table.Player(seat); ok {
+		v.Hand = p.Hand()
+	}
+	for _, p := range mgr.table.Players() {
+		v.HandSizes = append(v.HandSizes, len(p.Hand()))
+	}
+	return v
+}
+
+// Playable returns true if the card may be played on top of the discard pile.
+func (v CEView) Playable(c Card) bool {
+	return c.rank == v.Settings.Wild || c.suit == v.Suit || c.rank == v.Top.rank
+}
+
+// firstPlayable returns the index of the first playable card in the hand, or 0 if there is none.
+func firstPlayable(v CEView) int {
+	for i, c := range v.Hand {
+		if v.Playable(c) {
+			return i
+		}
+	}
+	return 0
+}
+
+// CEStrategy chooses the cards a Crazy Eights robot plays.
+type CEStrategy interface {
+	// Name identifies the strategy.
+	Name() string
+	// Choose returns the index in the player's hand of the card to play, and the suit to declare if it is wild.
+	// It is only asked to choose when the hand holds a playable card.
+	Choose(ctx context.Context, v CEView) (int, Suit, error)
+}
+
+// sheddingStrategy plays its costliest playable card, so that it is left holding as few points as possible,
+// and saves wild cards until nothing else can be played.
+type sheddingStrategy struct{}
+
+// NewSheddingStrategy creates a CEStrategy that sheds its costliest cards first, holding on to wild cards,
+// and declares the suit it holds most of.
+func NewSheddingStrategy() CEStrategy {
+	return sheddingStrategy{}
+}
+
+// Name identifies the strategy.
+func (sheddingStrategy) Name() string {
+	return "shedding"
+}
+
+// Choose plays the costliest playable card that is not wild, or else a wild card.
+func (sheddingStrategy) Choose(ctx context.Context, v CEView) (int, Suit, error) {
+	best := -1
+	for i, c := range v.Hand {
+		if !v.Playable(c) {
+			continue
+		} else if best < 0 {
+			best = i
+			continue
+		}
+		wild, bestWild := c.rank == v.Settings.Wild, v.Hand[best].rank == v.Settings.Wild
+		if bestWild && !wild || wild == bestWild && v.Settings.Points(c) > v.Settings.Points(v.Hand[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return 0, "", errors.New("no playable card")
+	}
+	c := v.Hand[best]
+	if c.rank != v.Settings.Wild {
+		return best, c.suit, nil
+	}
+	return best, mostHeld(v.Hand, best, v.Settings.Wild, c.suit), nil
+}
+
+// mostHeld returns the suit held most often in the hand, leaving out the card at index skip and wild cards.
+// Ties go to the suit listed first in Suits, and a hand with nothing else to follow declares the fallback.
+func mostHeld(h Hand, skip int, wild Rank, fallback Suit) Suit {
+	counts := make(map[Suit]int)
+	for i, c := range h {
+		if i != skip && c.rank != wild {
+			counts[c.suit]++
+		}
+	}
+	most := fallback
+	for _, suit := range Suits {
+		if counts[suit] > counts[most] {
+			most = suit
+		}
+	}
+	return most
+}
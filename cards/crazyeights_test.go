@@ -0,0 +1,180 @@
+package cards
+
+import (
+	"context"
+	"shuffle/utils"
+	"testing"
+)
+
+// newTestCEGame starts a quiet game of Crazy Eights between the named players, using the house rules.
+func newTestCEGame(names ...string) (*CEGameManager, []*CEPlayer) {
+	mgr := new(CEGameManager)
+	players := make([]*CEPlayer, len(names))
+	for i, name := range names {
+		players[i] = NewCEPlayer(name)
+	}
+	mgr.StartGame(players, nil)
+	return mgr, players
+}
+
+type PlayableResult struct {
+	card Card
+	want bool
+}
+
+func TestPlayable(t *testing.T) {
+	mgr, _ := newTestCEGame("Alice", "Bob")
+	mgr.discard(NewCard(Seven, Hearts))
+
+	tests := map[string]PlayableResult{
+		"same suit":  {NewCard(Two, Hearts), true},
+		"same rank":  {NewCard(Seven, Clubs), true},
+		"wild":       {NewCard(Eight, Spades), true},
+		"no match":   {NewCard(Queen, Spades), false},
+		"same card":  {NewCard(Seven, Hearts), true},
+		"other suit": {NewCard(Ace, Diamonds), false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			utils.Error(t, mgr.Playable(test.card), test.want)
+		})
+	}
+}
+
+func TestPlayWild(t *testing.T) {
+	mgr, players := newTestCEGame("Alice", "Bob")
+	alice, bob := players[0], players[1]
+	mgr.discard(NewCard(Seven, Hearts))
+	alice.ReplaceHand(Hand{NewCard(Eight, Spades), NewCard(Queen, Spades)})
+	bob.ReplaceHand(Hand{NewCard(Two, Hearts), NewCard(Three, Clubs)})
+
+	err := alice.Play(Hand{NewCard(Queen, Spades)})
+	utils.Error(t, err != nil, true, "error playing an unmatched card")
+
+	utils.Fatal(t, alice.PlayWild(NewCard(Eight, Spades), Clubs), nil)
+	top, suit := mgr.Top()
+	utils.Error(t, top, NewCard(Eight, Spades), "top of discard")
+	utils.Error(t, suit, Clubs, "suit to follow")
+	utils.Error(t, mgr.CurrPlayer().ID(), bob.ID(), "current player")
+
+	err = bob.Play(Hand{NewCard(Two, Hearts)})
+	utils.Error(t, err != nil, true, "error ignoring the declared suit")
+
+	utils.Fatal(t, bob.Play(Hand{NewCard(Three, Clubs)}), nil)
+	utils.Fatal(t, alice.Play(Hand{NewCard(Queen, Spades)}) != nil, true, "error ignoring the top suit")
+}
+
+func TestDrawAndWin(t *testing.T) {
+	mgr, players := newTestCEGame("Alice", "Bob")
+	alice, bob := players[0], players[1]
+	mgr.discard(NewCard(Seven, Hearts))
+	alice.ReplaceHand(Hand{NewCard(Two, Hearts)})
+	bob.ReplaceHand(Hand{NewCard(Queen, Spades), NewCard(Eight, Diamonds)})
+
+	_, err := mgr.Draw(alice)
+	utils.Error(t, err != nil, true, "error drawing with a playable card")
+
+	utils.Fatal(t, alice.Play(Hand{NewCard(Two, Hearts)}), nil)
+	utils.Error(t, mgr.Playing(), false, "playing after emptying a hand")
+	winner, ok := mgr.Winner()
+	utils.Fatal(t, ok, true, "winner declared")
+	utils.Error(t, winner.ID(), alice.ID())
+	utils.Error(t, mgr.Scores(), map[int]int{bob.ID(): 60})
+}
+
+func TestDrawUntilPlayable(t *testing.T) {
+	mgr, players := newTestCEGame("Alice", "Bob")
+	alice := players[0]
+	mgr.discard(NewCard(Seven, Hearts))
+	alice.ReplaceHand(Hand{NewCard(Queen, Spades)})
+
+	drawn, err := mgr.Draw(alice)
+	utils.Fatal(t, err, nil)
+	utils.Fatal(t, len(drawn) > 0, true, "cards drawn")
+	for _, c := range drawn[:len(drawn)-1] {
+		utils.Error(t, mgr.Playable(c), false, "for cards drawn before the last")
+	}
+	utils.Error(t, mgr.Playable(drawn[len(drawn)-1]), true, "for the last card drawn")
+	utils.Error(t, len(alice.Hand()), 1+len(drawn), "cards in hand")
+	utils.Error(t, mgr.CurrPlayer().ID(), alice.ID(), "current player")
+}
+
+func TestPlayWildInvalidSuit(t *testing.T) {
+	mgr, players := newTestCEGame("Alice", "Bob")
+	mgr.discard(NewCard(Seven, Hearts))
+	players[0].ReplaceHand(Hand{NewCard(Eight, Spades)})
+	err := players[0].PlayWild(NewCard(Eight, Spades), "elephants")
+	utils.Error(t, err != nil, true, "error declaring an invalid suit")
+	utils.Error(t, Suit("elephants").Valid(), false)
+	utils.Error(t, Hearts.Valid(), true)
+}
+
+func TestSheddingStrategy(t *testing.T) {
+	settings := *CEDefaultSettings
+	tests := map[string]struct {
+		hand     Hand
+		want     int
+		declared Suit
+	}{
+		"costliest card":  {Hand{NewCard(Two, Hearts), NewCard(King, Hearts), NewCard(Seven, Clubs)}, 1, Hearts},
+		"wild card saved": {Hand{NewCard(Eight, Clubs), NewCard(Three, Hearts)}, 1, Hearts},
+		"wild card last":  {Hand{NewCard(Queen, Spades), NewCard(Eight, Clubs), NewCard(Two, Spades), NewCard(Ace, Diamonds)}, 1, Spades},
+		"only wild cards": {Hand{NewCard(Eight, Clubs)}, 0, Clubs},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := CEView{Hand: test.hand, Top: NewCard(Seven, Hearts), Suit: Hearts, Settings: settings}
+			i, declared, err := NewSheddingStrategy().Choose(context.Background(), v)
+			utils.Fatal(t, err, nil)
+			utils.Error(t, i, test.want, "card")
+			utils.Error(t, declared, test.declared, "suit declared")
+		})
+	}
+}
+
+func TestCERobotsPlayToTheEnd(t *testing.T) {
+	mgr, _ := newTestCEGame("Alice", "Bob")
+	utils.Error(t, mgr.PlayTurn(context.Background()) != nil, true, "error playing a person's turn")
+
+	mgr = new(CEGameManager)
+	mgr.StartGameWithRobots(nil, 4, nil)
+	for turns := 0; mgr.Playing(); turns++ {
+		utils.Fatal(t, turns < 10000, true, "game finished in a reasonable number of turns")
+		utils.Fatal(t, mgr.PlayTurn(context.Background()), nil)
+	}
+	_, ok := mgr.Winner()
+	utils.Error(t, ok, true, "winner declared")
+}
+
+func TestCEDiscardPile(t *testing.T) {
+	deal := func() Card {
+		mgr := new(CEGameManager)
+		mgr.SetRandomizer(NewRngAt(2021))
+		mgr.StartGame([]*CEPlayer{NewCEPlayer("Alice"), NewCEPlayer("Bob")}, nil)
+		top, _ := mgr.Top()
+		return top
+	}
+	utils.Error(t, deal(), deal(), "seeded games deal alike")
+
+	mgr, players := newTestCEGame("Alice", "Bob")
+	alice := players[0]
+	mgr.discard(NewCard(Seven, Hearts))
+	alice.ReplaceHand(Hand{NewCard(Two, Hearts), NewCard(Queen, Clubs)})
+	utils.Fatal(t, alice.Play(Hand{NewCard(Two, Hearts)}), nil)
+	top, ok := mgr.dealer.TopDiscard()
+	utils.Error(t, ok, true)
+	utils.Error(t, top, NewCard(Two, Hearts), "the card played is discarded")
+
+	mgr.table.SetCurrSeat(alice.ID())
+	mgr.dealer = &dealer{draw: Shoe{NewCard(Ace, Clubs)}, discard: Shoe{NewCard(Queen, Spades), NewCard(King, Spades), NewCard(Seven, Hearts)},
+		rand: NewRngAt(1), keepTop: true}
+	mgr.declared = Hearts
+	drawn, err := mgr.Draw(alice)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, len(drawn), 3, "cards drawn before passing")
+	top, suit := mgr.Top()
+	utils.Error(t, top, NewCard(Seven, Hearts), "the top card is never reshuffled")
+	utils.Error(t, suit, Hearts)
+	utils.Error(t, mgr.dealer.DiscardSize(), 1)
+}
@@ -2,6 +2,8 @@ package cards
 
 import (
 	"shuffle/utils"
+
+	"github.com/pkg/errors"
 )
 
 // A Dealer perform actions on Shoes.
@@ -11,6 +13,19 @@ type Dealer interface {
 	Shuffle()
 	DealHand(size int) Hand
 	HandleDiscard(cards []Card)
+	DrawSize() int
+	DiscardSize() int
+	TopDiscard() (Card, bool)
+	Peek(n int) (Shoe, error)
+	Shuffles() int
+	Reshuffles() []ReshuffleEvent
+	SetDebug(on bool)
+}
+
+// ReshuffleEvent records a Dealer turning its discard pile into a new draw pile.
+type ReshuffleEvent struct {
+	Shuffle int // the ordinal of the shuffle that followed, counting from 1
+	Cards   int // the number of cards moved from the discard pile
 }
 
 // dealer is an implementation of Dealer.
@@ -18,10 +33,14 @@ type Dealer interface {
 // If the draw pile is exhausted during a deal, the dealer will automatically reshuffle
 // the discard pile and draw from it.
 type dealer struct {
-	drawIdx int
-	rand    Randomizer
-	draw    Shoe
-	discard Shoe
+	drawIdx    int
+	rand       Randomizer
+	draw       Shoe
+	discard    Shoe
+	debug      bool
+	shuffles   int
+	reshuffles []ReshuffleEvent
+	keepTop    bool // whether the top card of the discard pile stays in place when it is reshuffled
 }
 
 // NewDealer constructs a new dealer with the given number of decks, shuffled by default.
@@ -39,6 +58,7 @@ func NewDealer(numDecks int, rng Randomizer, shuffle ...bool) *dealer {
 func (d *dealer) Shuffle() {
 	d.rand.Shuffle(d.draw)
 	d.drawIdx = 0
+	d.shuffles++
 }
 
 // DealHand deals a number of Cards off the top of the draw pile.
@@ -46,7 +66,7 @@ func (d *dealer) Shuffle() {
 // the discard pile and draw from it.
 func (d *dealer) DealHand(size int) Hand {
 	// Calculate the size of the hand
-	sz := utils.Min(size, d.drawSize()+d.discardSize())
+	sz := utils.Min(size, d.drawSize()+d.reshuffleSize())
 	hand := make(Hand, sz)
 	if sz > 0 {
 		end := utils.Min(len(d.draw), d.drawIdx+sz)
@@ -63,11 +83,33 @@ func (d *dealer) DealHand(size int) Hand {
 }
 
 // reshuffle shuffles the discard pile and sets it as the draw pile.
+// If the dealer keeps the top card, it is left behind as the new discard pile.
+// It is only recorded as a ReshuffleEvent if it moves any cards.
 func (d *dealer) reshuffle() {
-	d.draw, d.discard = d.discard, NewShoe(0)
+	pile, kept := d.discard, NewShoe(0)
+	if d.keepTop && len(pile) > 0 {
+		pile, kept = pile[:len(pile)-1], append(kept, pile[len(pile)-1])
+	}
+	if len(pile) > 0 {
+		d.reshuffles = append(d.reshuffles, ReshuffleEvent{Shuffle: d.shuffles + 1, Cards: len(pile)})
+	}
+	d.draw, d.discard = pile, kept
 	d.Shuffle()
 }
 
+// copy returns a Dealer with copies of this Dealer's piles, which shuffles just as this Dealer will.
+// If the Randomizer cannot be forked, the copy shares it.
+func (d *dealer) copy() *dealer {
+	c := *d
+	if f, ok := d.rand.(forker); ok {
+		c.rand = f.fork()
+	}
+	c.draw = append(Shoe{}, d.draw...)
+	c.discard = append(Shoe{}, d.discard...)
+	c.reshuffles = append([]ReshuffleEvent{}, d.reshuffles...)
+	return &c
+}
+
 // HandleDiscard adds the given cards to the discard pile.
 func (d *dealer) HandleDiscard(cards []Card) {
 	d.discard = append(d.discard, cards...)
@@ -86,12 +128,69 @@ func (d *dealer) replaceShoe(numDecks int) {
 	d.drawIdx = 0
 }
 
+// SetDebug enables or disables debug mode, which permits peeking at the draw pile.
+func (d *dealer) SetDebug(on bool) {
+	d.debug = on
+}
+
+// DrawSize returns the number of cards remaining in the draw pile.
+func (d dealer) DrawSize() int {
+	return d.drawSize()
+}
+
+// DiscardSize returns the number of cards in the discard pile.
+func (d dealer) DiscardSize() int {
+	return d.discardSize()
+}
+
+// TopDiscard returns the most recently discarded card.
+// It returns false if the discard pile is empty.
+func (d dealer) TopDiscard() (Card, bool) {
+	if len(d.discard) == 0 {
+		return Card{}, false
+	}
+	return d.discard[len(d.discard)-1], true
+}
+
+// Peek returns a copy of the top n cards of the draw pile, in the order they will be dealt.
+// It returns an error unless the dealer is in debug mode.
+func (d dealer) Peek(n int) (Shoe, error) {
+	if !d.debug {
+		return nil, errors.New("peeking requires debug mode")
+	}
+	pile := d.drawPile()
+	n = utils.Max(utils.Min(n, len(pile)), 0)
+	s := make(Shoe, n)
+	copy(s, pile[:n])
+	return s, nil
+}
+
+// Shuffles returns the number of times the draw pile has been shuffled.
+func (d dealer) Shuffles() int {
+	return d.shuffles
+}
+
+// Reshuffles returns a copy of the history of discard piles being reshuffled into the draw pile.
+func (d dealer) Reshuffles() []ReshuffleEvent {
+	r := make([]ReshuffleEvent, len(d.reshuffles))
+	copy(r, d.reshuffles)
+	return r
+}
+
 // drawSize returns the size of the draw pile.
 func (d dealer) drawSize() int {
 	return len(d.draw) - d.drawIdx
 }
 
-// drawSize returns the size of the draw pile.
+// reshuffleSize returns the number of cards in the discard pile that can be reshuffled into the draw pile.
+func (d dealer) reshuffleSize() int {
+	if d.keepTop {
+		return utils.Max(len(d.discard)-1, 0)
+	}
+	return len(d.discard)
+}
+
+// discardSize returns the size of the discard pile.
 func (d dealer) discardSize() int {
 	return len(d.discard)
 }
@@ -133,3 +133,44 @@ func TestNewDealer(t *testing.T) {
 		})
 	}
 }
+
+func TestIntrospection(t *testing.T) {
+	SetupTest(t)
+	random.EXPECT().Shuffle(gomock.Any()).Times(2)
+
+	var dl Dealer = NewDealer(1, random, false)
+	_, ok := dl.TopDiscard()
+	utils.Error(t, ok, false, "top discard of an empty pile")
+
+	_, err := dl.Peek(3)
+	utils.Error(t, err != nil, true, "peek outside debug mode")
+
+	dl.SetDebug(true)
+	peeked, _ := dl.Peek(3)
+	utils.Error(t, peeked, Shoe{NewCard(Ace, Clubs), NewCard(Two, Clubs), NewCard(Three, Clubs)})
+	peeked[0] = NewCard(King, Hearts)
+	again, _ := dl.Peek(1)
+	utils.Error(t, again[0], NewCard(Ace, Clubs), "after mutating a peeked copy")
+
+	hand := dl.DealHand(52)
+	utils.Error(t, dl.DrawSize(), 0, "draw cards remaining")
+	utils.Error(t, dl.Shuffles(), 1, "shuffles after exhaustion")
+
+	dl.HandleDiscard(hand[:10])
+	top, ok := dl.TopDiscard()
+	utils.Error(t, ok, true, "top discard of a non-empty pile")
+	utils.Error(t, top, hand[9])
+	utils.Error(t, dl.DiscardSize(), 10, "cards in discard")
+
+	dl.DealHand(1)
+	utils.Error(t, dl.Shuffles(), 2, "shuffles after reshuffle")
+	utils.Error(t, dl.Reshuffles(), []ReshuffleEvent{{Shuffle: 2, Cards: 10}}, "only reshuffles that move cards")
+}
+
+func TestCopyShufflesAlike(t *testing.T) {
+	d := NewDealer(1, NewRngAt(2021))
+	c := d.copy()
+	d.HandleDiscard(d.DealHand(CardsPerDeck))
+	c.HandleDiscard(c.DealHand(CardsPerDeck))
+	utils.Error(t, c.DealHand(5), d.DealHand(5), "cards dealt after reshuffling")
+}
@@ -0,0 +1,82 @@
+package cards
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// EventLog writes Events as JSON Lines, one Event per line, in the order they are emitted.
+// Subscribe its Record method to an EventStream to keep an append-only log of a game.
+// It is safe for concurrent use.
+type EventLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+	err error
+}
+
+// NewEventLog creates an EventLog that writes to w.
+func NewEventLog(w io.Writer) *EventLog {
+	return &EventLog{w: w, enc: json.NewEncoder(w)}
+}
+
+// OpenEventLog creates an EventLog that appends to the file at the given path,
+// creating the file if it does not exist. Close the log to close the file.
+func OpenEventLog(path string) (*EventLog, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open event log")
+	}
+	return NewEventLog(f), nil
+}
+
+// Record writes a single Event to the log.
+// Once a write fails, no more Events are written, and Err reports the failure.
+func (l *EventLog) Record(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = errors.Wrap(l.enc.Encode(e), "could not write event log")
+	}
+}
+
+// Err returns the first error encountered while writing the log, if any.
+func (l *EventLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Close closes the log's writer, if it can be closed, and returns the first error encountered by the log.
+func (l *EventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.w.(io.Closer); ok {
+		if err := c.Close(); err != nil && l.err == nil {
+			l.err = errors.Wrap(err, "could not close event log")
+		}
+	}
+	return l.err
+}
+
+// ReadEventLog reads every Event from a JSON Lines log written by an EventLog.
+func ReadEventLog(r io.Reader) ([]Event, error) {
+	var events []Event
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return events, errors.Wrapf(err, "invalid event on line %d", line)
+		}
+		events = append(events, e)
+	}
+	return events, errors.Wrap(sc.Err(), "could not read event log")
+}
@@ -0,0 +1,62 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"shuffle/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventLog(t *testing.T) {
+	var b bytes.Buffer
+	log := NewEventLog(&b)
+	mgr := new(NNGameManager)
+	mgr.SetRandomizer(NewRngAt(2021))
+	mgr.SetEvents(NewEventStream())
+	clock := time.Date(2021, time.December, 25, 9, 0, 0, 0, time.UTC)
+	mgr.events.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	mgr.events.Subscribe(log.Record)
+
+	players := []*NNPlayer{NewNNRobot("Alice", NewCautiousStrategy()), NewNNRobot("Bob", NewAggressiveStrategy())}
+	mgr.StartGame(players, 0, nil)
+	for mgr.Playing() {
+		utils.Fatal(t, mgr.PlayTurn(context.Background()), nil)
+	}
+	utils.Fatal(t, log.Err(), nil)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	utils.Error(t, len(lines), len(mgr.Events().History()), "one line per event")
+	utils.Error(t, strings.HasPrefix(lines[0], `{"seq":1,"time":"2021-12-25T09:00:01Z","game":"99","type":"game_created"`), true, "first line")
+
+	events, err := ReadEventLog(&b)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, events, mgr.Events().History(), "events read back from the log")
+}
+
+func TestOpenEventLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for i := 1; i <= 2; i++ {
+		log, err := OpenEventLog(path)
+		utils.Fatal(t, err, nil)
+		log.Record(Event{Seq: i, Type: EventCardPlayed, Cards: Hand{NewCard(Ten, Hearts)}, Count: 90})
+		utils.Fatal(t, log.Close(), nil)
+	}
+
+	f, err := os.Open(path)
+	utils.Fatal(t, err, nil)
+	defer f.Close()
+	events, err := ReadEventLog(f)
+	utils.Fatal(t, err, nil)
+	utils.Fatal(t, len(events), 2, "events appended")
+	utils.Error(t, events[1].Cards, Hand{NewCard(Ten, Hearts)})
+
+	_, err = ReadEventLog(strings.NewReader("{\"seq\":1}\nnot json\n"))
+	utils.Error(t, err != nil, true, "error for an invalid line")
+}
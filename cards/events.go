@@ -0,0 +1,155 @@
+package cards
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies what happened in a game.
+type EventType string
+
+const (
+	EventGameCreated  EventType = "game_created"
+	EventDealt        EventType = "dealt"
+	EventCardPlayed   EventType = "card_played"
+	EventReversed     EventType = "reversed"
+	EventDrew         EventType = "drew"
+	EventBusted       EventType = "busted"
+	EventLifeLost     EventType = "life_lost"
+	EventEliminated   EventType = "eliminated"
+	EventDeclared     EventType = "declared"
+	EventPassed       EventType = "passed"
+	EventWon          EventType = "won"
+	EventBet          EventType = "bet"
+	EventStood        EventType = "stood"
+	EventDoubled      EventType = "doubled"
+	EventSplit        EventType = "split"
+	EventRevealed     EventType = "revealed"
+	EventSettled      EventType = "settled"
+	EventHandOver     EventType = "hand_over"
+	EventGameEnded    EventType = "game_ended"
+	EventUndone       EventType = "undone"
+	EventTurnWarning  EventType = "turn_warning"
+	EventTimedOut     EventType = "timed_out"
+	EventDisconnected EventType = "disconnected"
+	EventReconnected  EventType = "reconnected"
+	EventReplaced     EventType = "replaced"
+	EventChat         EventType = "chat"
+	EventEmote        EventType = "emote"
+	EventMuted        EventType = "muted"
+	EventUnmuted      EventType = "unmuted"
+	EventPaused       EventType = "paused"
+	EventResumed      EventType = "resumed"
+	EventKicked       EventType = "kicked"
+	EventRobotAdded   EventType = "robot_added"
+	EventRobotRemoved EventType = "robot_removed"
+	EventRulesChanged EventType = "rules_changed"
+)
+
+// NoSeat is the Seat of an Event that concerns the whole table rather than a single Player.
+const NoSeat = -1
+
+// Event is a public record of an action taken by a GameManager.
+// Events never reveal hidden information, such as the cards a Player draws.
+type Event struct {
+	Seq       int           `json:"seq"`
+	Time      time.Time     `json:"time"`
+	Game      string        `json:"game"`
+	Type      EventType     `json:"type"`
+	Seat      int           `json:"seat"`
+	Cards     Hand          `json:"cards,omitempty"`
+	Count     int           `json:"count"`               // the game's running score after the event, e.g. the count in 99
+	Suit      Suit          `json:"suit,omitempty"`      // the suit declared with a wild card, if any
+	Lives     int           `json:"lives"`               // the lives the Player has left, for events that change them
+	Remaining time.Duration `json:"remaining,omitempty"` // the time the Player has left to take their turn, for warnings
+	Names     []string      `json:"names,omitempty"`     // the names of the Players in seating order, for events that seat them
+	Name      string        `json:"name,omitempty"`      // the name of the Player who spoke, was muted, kicked or seated, which may be before they are seated
+	Text      string        `json:"text,omitempty"`      // what was said, for chat and emotes
+	Table     string        `json:"table,omitempty"`     // the table the Event happened at, in logs of many tables
+}
+
+// EventStream is an ordered, append-only history of Events that can be observed as it grows.
+// It is safe for concurrent use.
+type EventStream struct {
+	mu        sync.Mutex
+	history   []Event
+	listeners []listener
+	nextID    int
+	now       func() time.Time
+}
+
+// listener is a function subscribed to an EventStream, identified so it can be unsubscribed.
+type listener struct {
+	id int
+	f  func(Event)
+}
+
+// NewEventStream creates an empty EventStream.
+func NewEventStream() *EventStream {
+	return &EventStream{now: time.Now}
+}
+
+// Emit stamps an Event with the next sequence number and the current time,
+// records it, and notifies every listener.
+// It returns the stamped Event.
+func (s *EventStream) Emit(e Event) Event {
+	s.mu.Lock()
+	e.Seq = len(s.history) + 1
+	e.Time = s.now()
+	s.history = append(s.history, e)
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	for _, l := range listeners {
+		l.f(e)
+	}
+	return e
+}
+
+// Subscribe registers a listener that is called with every Event emitted from now on.
+// Listeners are called synchronously, on the goroutine emitting the Event.
+// It returns a function that unsubscribes the listener.
+func (s *EventStream) Subscribe(f func(Event)) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	id := s.nextID
+	s.listeners = append(s.listeners[:len(s.listeners):len(s.listeners)], listener{id, f})
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, l := range s.listeners {
+			if l.id == id {
+				s.listeners = append(s.listeners[:i:i], s.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// History returns a copy of every Event emitted so far.
+func (s *EventStream) History() []Event {
+	return s.Since(0)
+}
+
+// LastSeq returns the sequence number of the latest Event emitted, or 0 if there are none.
+func (s *EventStream) LastSeq() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.history)
+}
+
+// Since returns a copy of every Event with a sequence number greater than seq.
+func (s *EventStream) Since(seq int) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq < 0 {
+		seq = 0
+	}
+	if seq > len(s.history) {
+		seq = len(s.history)
+	}
+	e := make([]Event, len(s.history)-seq)
+	copy(e, s.history[seq:])
+	return e
+}
@@ -0,0 +1,274 @@
+package cards
+
+import (
+	"context"
+	"math"
+	mrand "math/rand"
+	"shuffle/utils"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExpertConfig tunes the search performed by the expert strategy.
+type ExpertConfig struct {
+	Iterations  int           // the most playouts to run per move
+	Depth       int           // the most cards to play in each playout, over as many rounds as it takes
+	Budget      time.Duration // the most time to spend per move, in addition to any context deadline; 0 for none
+	Exploration float64       // the UCB1 exploration constant
+}
+
+// ExpertDefaults is the configuration of the built-in "expert" strategy.
+// It has no time budget, so that an expert seeded alike always makes the same choices.
+var ExpertDefaults = ExpertConfig{
+	Iterations:  2000,
+	Depth:       40,
+	Exploration: math.Sqrt2,
+}
+
+// expertStrategy chooses cards by determinized Monte Carlo search.
+// Each playout deals the unseen cards into plausible hands for the other players and a draw pile,
+// then plays the game on from the chosen card, with every player following a default policy,
+// and scores the card by the expert's survival: its share of the lives left at the table.
+// The cards in the hand are treated as the arms of a UCB1 bandit, and the card played most often wins.
+type expertStrategy struct {
+	cfg ExpertConfig
+	r   *mrand.Rand
+}
+
+// NewExpertStrategy creates a strategy that searches for the card least likely to make it bust.
+func NewExpertStrategy(cfg ExpertConfig, seed int64) NNStrategy {
+	return &expertStrategy{cfg: cfg, r: mrand.New(mrand.NewSource(seed))}
+}
+
+// Name returns the name of the strategy.
+func (s *expertStrategy) Name() string {
+	return "expert"
+}
+
+// expertArm holds the playout statistics of a single card in the hand.
+type expertArm struct {
+	index  int
+	visits int
+	reward float64
+}
+
+// Choose searches until its iterations, its budget or the context run out, then returns the card
+// played in the most playouts. If every card busts, or the search is cut short before it begins,
+// it falls back to the cautious strategy.
+func (s *expertStrategy) Choose(ctx context.Context, v NNView) (int, error) {
+	if len(v.Hand) == 0 {
+		return 0, errors.New("no cards to choose from")
+	}
+	safe := safeCards(v)
+	if len(safe) <= 1 {
+		return NewCautiousStrategy().Choose(ctx, v)
+	}
+	if s.cfg.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Budget)
+		defer cancel()
+	}
+
+	arms := make([]*expertArm, len(safe))
+	for i, idx := range safe {
+		arms[i] = &expertArm{index: idx}
+	}
+	unseen := v.Unseen()
+	for n := 0; n < s.cfg.Iterations && ctx.Err() == nil; n++ {
+		arm := s.selectArm(arms, n)
+		w := s.determinize(v, unseen)
+		arm.visits++
+		arm.reward += w.playout(arm.index, s.cfg.Depth, s.r)
+	}
+
+	best := arms[0]
+	for _, arm := range arms[1:] {
+		if arm.visits > best.visits || (arm.visits == best.visits && arm.reward > best.reward) {
+			best = arm
+		}
+	}
+	if best.visits == 0 {
+		return NewCautiousStrategy().Choose(ctx, v)
+	}
+	return best.index, nil
+}
+
+// selectArm chooses the card to explore in the next playout, trying every card once before applying UCB1.
+func (s *expertStrategy) selectArm(arms []*expertArm, n int) *expertArm {
+	var best *expertArm
+	bestScore := math.Inf(-1)
+	for _, arm := range arms {
+		if arm.visits == 0 {
+			return arm
+		}
+		score := arm.reward/float64(arm.visits) + s.cfg.Exploration*math.Sqrt(math.Log(float64(n))/float64(arm.visits))
+		if score > bestScore {
+			best, bestScore = arm, score
+		}
+	}
+	return best
+}
+
+// nnWorld is a determinized game of 99: every hand and the order of the draw pile are known.
+type nnWorld struct {
+	settings  *NNGameSettings
+	me        int
+	seat      int
+	count     int
+	direction int
+	active    []bool
+	lives     []int
+	hands     []Hand
+	draw      Shoe
+}
+
+// determinize deals the unseen cards into hands for the other active players, according to their
+// hand sizes, and shuffles the remainder into a draw pile.
+func (s *expertStrategy) determinize(v NNView, unseen Shoe) *nnWorld {
+	pool := append(Shoe{}, unseen...)
+	s.r.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	w := &nnWorld{
+		settings:  &v.Settings,
+		me:        v.Seat,
+		seat:      v.Seat,
+		count:     v.Count,
+		direction: v.Direction,
+		active:    append([]bool{}, v.Active...),
+		lives:     make([]int, len(v.Active)),
+		hands:     make([]Hand, len(v.Active)),
+	}
+	copy(w.lives, v.Lives)
+	for seat, active := range v.Active {
+		if seat == v.Seat {
+			w.hands[seat] = append(Hand{}, v.Hand...)
+		} else if active {
+			n := v.HandSizes[seat]
+			if n > len(pool) {
+				n = len(pool)
+			}
+			w.hands[seat], pool = Hand(pool[:n]), pool[n:]
+		}
+	}
+	w.draw = pool
+	return w
+}
+
+// playout plays the given card from the searching player's hand, then plays the game on
+// with every player choosing a random card that does not bust. Whoever busts loses a life,
+// and is eliminated when they have none left; the next round is then dealt from a fresh shoe.
+// It returns the searching player's survival once the given number of cards have been played,
+// or as soon as it is eliminated or the last player left.
+func (w *nnWorld) playout(index int, depth int, r *mrand.Rand) float64 {
+	for ply := 0; ply < depth; ply++ {
+		hand := w.hands[w.seat]
+		if len(hand) == 0 {
+			return w.survival()
+		}
+		if ply > 0 {
+			index = w.policy(hand, r)
+		}
+		c := hand[index]
+		w.hands[w.seat] = append(hand[:index:index], hand[index+1:]...)
+
+		toAdd, _ := w.settings.ScoreRank(w.count, c.rank)
+		w.count += toAdd
+		if w.count > w.settings.MaxCount {
+			if w.bust(r) {
+				return w.survival()
+			}
+			continue
+		}
+		if c.rank == w.settings.WildCards.Reverse {
+			w.direction *= -1
+		}
+		if len(w.draw) > 0 {
+			w.hands[w.seat] = append(w.hands[w.seat], w.draw[0])
+			w.draw = w.draw[1:]
+		}
+		w.advance()
+	}
+	return w.survival()
+}
+
+// survival estimates the searching player's chance of outlasting everyone else as its share of the lives left at the table.
+// It is 0 once the searching player is eliminated, and 1 once it is the last player left.
+func (w *nnWorld) survival() float64 {
+	if !w.active[w.me] {
+		return 0
+	}
+	total := 0
+	for seat, a := range w.active {
+		if a {
+			total += w.lives[seat]
+		}
+	}
+	if total <= 0 {
+		return 0
+	}
+	return float64(w.lives[w.me]) / float64(total)
+}
+
+// bust takes a life from the current player, eliminating them if they have none left,
+// and deals the next round to the players still in the game, beginning after them.
+// It returns true if the game is over for the searching player: it has been eliminated, or it is the last player left.
+func (w *nnWorld) bust(r *mrand.Rand) bool {
+	w.lives[w.seat]--
+	if w.lives[w.seat] <= 0 {
+		w.active[w.seat] = false
+		if w.seat == w.me {
+			return true
+		}
+	}
+	active := 0
+	for _, a := range w.active {
+		if a {
+			active++
+		}
+	}
+	if active <= 1 {
+		return true
+	}
+
+	pool := NewShoe(w.settings.Decks(active))
+	r.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	for seat, a := range w.active {
+		w.hands[seat] = nil
+		if a {
+			n := utils.Min(w.settings.CardsPerPlayer, len(pool))
+			w.hands[seat], pool = Hand(pool[:n]), pool[n:]
+		}
+	}
+	w.draw = pool
+	w.count = 0
+	w.direction = 1
+	w.advance()
+	return false
+}
+
+// policy chooses a random card from the hand that does not bust, or any card if every card busts.
+func (w *nnWorld) policy(hand Hand, r *mrand.Rand) int {
+	var safe []int
+	for i, c := range hand {
+		if toAdd, err := w.settings.ScoreRank(w.count, c.rank); err == nil && w.count+toAdd <= w.settings.MaxCount {
+			safe = append(safe, i)
+		}
+	}
+	if len(safe) > 0 {
+		return safe[r.Intn(len(safe))]
+	}
+	return r.Intn(len(hand))
+}
+
+// advance passes the turn to the next active player, in the direction of play.
+func (w *nnWorld) advance() {
+	n := len(w.active)
+	for step := 1; step <= n; step++ {
+		seat := ((w.seat+step*w.direction)%n + n) % n
+		if w.active[seat] {
+			w.seat = seat
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cards
+
+import (
+	"context"
+	mrand "math/rand"
+	"shuffle/utils"
+	"testing"
+	"time"
+)
+
+// expertTestView is a position where one card is plainly better than the others:
+// at 90, the 9 takes the count to 99 and keeps both 10s to escape with,
+// while a 10 leaves the next player plenty of room.
+var expertTestView = NNView{
+	Seat:      0,
+	Hand:      Hand{NewCard(Ten, Hearts), NewCard(Nine, Clubs), NewCard(Ten, Spades)},
+	Count:     90,
+	Direction: 1,
+	Lives:     []int{3, 3},
+	HandSizes: []int{3, 3},
+	Active:    []bool{true, true},
+	Settings:  *NNDefaultSettings,
+}
+
+func TestExpertFindsTheNine(t *testing.T) {
+	cfg := ExpertDefaults
+	cfg.Budget = 0
+	s := NewExpertStrategy(cfg, 2021)
+	i, err := s.Choose(context.Background(), expertTestView)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, expertTestView.Hand[i], NewCard(Nine, Clubs))
+}
+
+func TestExpertRespectsDeadline(t *testing.T) {
+	cfg := ExpertDefaults
+	cfg.Iterations = 1 << 30
+	cfg.Budget = 0
+	s := NewExpertStrategy(cfg, 2021)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	i, err := s.Choose(ctx, expertTestView)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, time.Since(start) < time.Second, true, "search stopped at the deadline")
+	utils.Error(t, expertTestView.Safe(expertTestView.Hand[i]), true, "chose a safe card")
+}
+
+func TestExpertOnlyOneSafeCard(t *testing.T) {
+	v := expertTestView
+	v.Count = 97
+	v.Hand = Hand{NewCard(Queen, Hearts), NewCard(Ten, Clubs), NewCard(Jack, Spades)}
+	i, err := NewExpertStrategy(ExpertDefaults, 1).Choose(context.Background(), v)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, v.Hand[i], NewCard(Ten, Clubs))
+}
+
+func TestExpertIsRepeatable(t *testing.T) {
+	a, err := NewExpertStrategy(ExpertDefaults, 7).Choose(context.Background(), expertTestView)
+	utils.Fatal(t, err, nil)
+	for n := 0; n < 3; n++ {
+		b, err := NewExpertStrategy(ExpertDefaults, 7).Choose(context.Background(), expertTestView)
+		utils.Fatal(t, err, nil)
+		utils.Error(t, b, a, "the choice of an expert seeded alike")
+	}
+}
+
+func TestExpertPlayoutScoresSurvival(t *testing.T) {
+	r := mrand.New(mrand.NewSource(1))
+	world := func(lives ...int) *nnWorld {
+		return &nnWorld{
+			settings:  NNDefaultSettings,
+			count:     95,
+			direction: 1,
+			active:    []bool{true, true},
+			lives:     lives,
+			hands:     []Hand{{NewCard(Queen, Hearts)}, {NewCard(Jack, Spades)}},
+		}
+	}
+	utils.Error(t, world(1, 3).playout(0, 10, r), 0.0, "the expert busts on its last life")
+	w := world(2, 3)
+	w.count = 90
+	w.hands[0] = Hand{NewCard(Nine, Clubs)}
+	utils.Error(t, w.playout(0, 2, r), 0.5, "the other player busts")
+	w = world(2, 1)
+	w.count = 90
+	w.hands[0] = Hand{NewCard(Nine, Clubs)}
+	utils.Error(t, w.playout(0, 2, r), 1.0, "the other player is eliminated")
+}
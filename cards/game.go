@@ -0,0 +1,178 @@
+package cards
+
+import (
+	"shuffle/utils"
+)
+
+// GameManager is an interface for entities that specify and enforce the rules of a specific game.
+// It contains the logic for starting and ending games, maintains the order of play,
+// validates player moves, and shifts cards between players and dealers.
+// Each game provides its own StartGame, which accepts that game's Rules.
+type GameManager interface {
+	Deal()
+	Play(p Player, h Hand) error
+	EndGame()
+	Playing() bool
+	CurrPlayer() Player
+	Table() *Table
+	Events() *EventStream
+}
+
+// Rules is an interface for the settings of a specific game.
+// It tells the framework how large a Shoe to use and how many cards to deal each Player.
+type Rules interface {
+	Game() string
+	HandSize() int
+	Decks(numPlayers int) int
+}
+
+// dealTable creates a Dealer with a Shoe sized by the Rules and deals a fresh Hand
+// to every Player still in play at the Table.
+func dealTable(r Rules, t *Table, rng Randomizer) *dealer {
+	remaining := t.Remaining()
+	d := NewDealer(r.Decks(len(remaining)), rng, true)
+	for _, p := range remaining {
+		p.ReplaceHand(d.DealHand(r.HandSize()))
+	}
+	return d
+}
+
+// Table seats Players in a circle and keeps track of whose turn it is.
+// A Player's ID is the number of the seat they occupy, in the order they sat down.
+// Players who have been eliminated keep their seat but are skipped over.
+type Table struct {
+	seats     []Player
+	out       []bool
+	curr      int
+	direction int
+}
+
+// NewTable creates an empty Table, with play proceeding clockwise from the first seat.
+func NewTable() *Table {
+	return &Table{direction: 1}
+}
+
+// Sit seats a Player at the next free seat and assigns them to a GameManager.
+// It returns the Player's seat number.
+func (t *Table) Sit(p Player, mgr GameManager) int {
+	id := len(t.seats)
+	p.join(id, mgr)
+	t.seats = append(t.seats, p)
+	t.out = append(t.out, false)
+	return id
+}
+
+// Size returns the number of seats at the Table, including those of eliminated Players.
+func (t *Table) Size() int {
+	return len(t.seats)
+}
+
+// Players returns a copy of the Players at the Table in seating order.
+func (t *Table) Players() []Player {
+	p := make([]Player, len(t.seats))
+	copy(p, t.seats)
+	return p
+}
+
+// tableNames returns the names of the Players at the Table in seating order.
+func tableNames(t *Table) []string {
+	names := make([]string, t.Size())
+	for i, p := range t.seats {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Player returns the Player in the given seat.
+// It returns false if the seat does not exist.
+func (t *Table) Player(id int) (Player, bool) {
+	if 0 <= id && id < len(t.seats) {
+		return t.seats[id], true
+	}
+	return nil, false
+}
+
+// CurrPlayer returns the Player who is currently taking their turn.
+func (t *Table) CurrPlayer() Player {
+	if len(t.seats) == 0 {
+		return nil
+	}
+	return t.seats[t.curr]
+}
+
+// CurrSeat returns the seat number of the Player who is currently taking their turn.
+func (t *Table) CurrSeat() int {
+	return t.curr
+}
+
+// SetCurrSeat passes the turn to the Player in the given seat, if it exists.
+func (t *Table) SetCurrSeat(id int) {
+	if 0 <= id && id < len(t.seats) {
+		t.curr = id
+	}
+}
+
+// Direction returns 1 if play proceeds clockwise and -1 if play proceeds counterclockwise.
+func (t *Table) Direction() int {
+	return t.direction
+}
+
+// Reverse reverses the direction of play.
+func (t *Table) Reverse() {
+	t.direction *= -1
+}
+
+// ResetDirection restores clockwise play.
+func (t *Table) ResetDirection() {
+	t.direction = 1
+}
+
+// Advance passes the turn to the next active Player in the circle, according to the direction of play.
+func (t *Table) Advance() {
+	t.curr = t.next(t.curr)
+}
+
+// Next returns the seat of the next active Player after the current one, without advancing play.
+func (t *Table) Next() int {
+	return t.next(t.curr)
+}
+
+// next returns the seat of the next active Player after the given seat.
+// If no other Player is active, the given seat is returned.
+func (t *Table) next(from int) int {
+	seat := from
+	for range t.seats {
+		if curr, err := utils.Mod(seat+t.direction, len(t.seats)); err == nil {
+			seat = curr
+		} else {
+			return 0
+		}
+		if !t.out[seat] {
+			return seat
+		}
+	}
+	return from
+}
+
+// Eliminate removes the Player in the given seat from play.
+func (t *Table) Eliminate(id int) {
+	if 0 <= id && id < len(t.out) {
+		t.out[id] = true
+	}
+}
+
+// Active returns true if the Player in the given seat is still in play.
+func (t *Table) Active(id int) bool {
+	return 0 <= id && id < len(t.out) && !t.out[id]
+}
+
+// Remaining returns the Players who are still in play, in seating order.
+func (t *Table) Remaining() []Player {
+	var p []Player
+	for id, player := range t.seats {
+		if !t.out[id] {
+			p = append(p, player)
+		}
+	}
+	return p
+}
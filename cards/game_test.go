@@ -0,0 +1,90 @@
+package cards
+
+import (
+	"shuffle/utils"
+	"testing"
+)
+
+type AdvanceResult struct {
+	seats      int
+	eliminated []int
+	reverse    bool
+	want       []int
+}
+
+func TestTableAdvance(t *testing.T) {
+	tests := map[string]AdvanceResult{
+		"clockwise":                 {4, nil, false, []int{1, 2, 3, 0, 1}},
+		"counterclockwise":          {4, nil, true, []int{3, 2, 1, 0, 3}},
+		"skips eliminated":          {4, []int{1, 2}, false, []int{3, 0, 3, 0}},
+		"skips eliminated reversed": {4, []int{3}, true, []int{2, 1, 0, 2}},
+		"single player":             {1, nil, false, []int{0, 0}},
+		"last player standing":      {3, []int{0, 2}, false, []int{1, 1}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mgr := new(NNGameManager)
+			table := NewTable()
+			for i := 0; i < test.seats; i++ {
+				table.Sit(NewNNPlayer("p"), mgr)
+			}
+			for _, id := range test.eliminated {
+				table.Eliminate(id)
+			}
+			if test.reverse {
+				table.Reverse()
+			}
+			if !table.Active(0) {
+				table.SetCurrSeat(1)
+			}
+			got := make([]int, len(test.want))
+			for i := range got {
+				table.Advance()
+				got[i] = table.CurrSeat()
+			}
+			utils.Error(t, got, test.want)
+		})
+	}
+}
+
+func TestTableSit(t *testing.T) {
+	mgr := new(NNGameManager)
+	table := NewTable()
+	alice, bob := NewNNPlayer("Alice"), NewNNPlayer("Bob")
+	utils.Error(t, table.Sit(alice, mgr), 0, "seat of first player")
+	utils.Error(t, table.Sit(bob, mgr), 1, "seat of second player")
+	utils.Error(t, bob.ID(), 1, "id of second player")
+
+	got, ok := table.Player(1)
+	utils.Error(t, ok, true)
+	utils.Error(t, got.Name(), "Bob")
+	_, ok = table.Player(2)
+	utils.Error(t, ok, false, "for an empty seat")
+
+	table.Eliminate(0)
+	utils.Error(t, len(table.Remaining()), 1, "players remaining")
+	utils.Error(t, table.Size(), 2, "seats")
+}
+
+func TestEventStream(t *testing.T) {
+	s := NewEventStream()
+	s.Emit(Event{Type: EventGameCreated, Seat: NoSeat})
+
+	var heard, others []int
+	unsubscribe := s.Subscribe(func(e Event) { heard = append(heard, e.Seq) })
+	s.Subscribe(func(e Event) { others = append(others, e.Seq) })
+	s.Emit(Event{Type: EventCardPlayed, Seat: 0})
+	s.Emit(Event{Type: EventDrew, Seat: 0})
+	unsubscribe()
+	s.Emit(Event{Type: EventCardPlayed, Seat: 1})
+	unsubscribe()
+
+	utils.Error(t, heard, []int{2, 3}, "sequence numbers heard by listener")
+	utils.Error(t, others, []int{2, 3, 4}, "sequence numbers heard by another listener")
+	utils.Error(t, len(s.History()), 4, "events in history")
+	since := s.Since(2)
+	utils.Fatal(t, len(since), 2, "events since 2")
+	utils.Error(t, since[0].Type, EventDrew)
+	utils.Error(t, len(s.Since(10)), 0, "events since the future")
+}
@@ -0,0 +1,93 @@
+package cards
+
+// CardHint describes the consequences of playing a single card in a game of 99.
+type CardHint struct {
+	Index     int     // the position of the card in the player's hand
+	Card      Card    // the card analyzed
+	Count     int     // the count after the card is played
+	Safe      bool    // whether the card can be played without busting
+	Next      int     // the seat of the player who would play next
+	BustsNext float64 // the probability that the next player is forced to bust
+}
+
+// Unseen returns the cards the player cannot account for: every card in the Shoe,
+// except those in their own hand and those in the public discard pile.
+// The Shoe is assumed to be sized by the game's settings for the players still in the game.
+func (v NNView) Unseen() Shoe {
+	active := 0
+	for _, a := range v.Active {
+		if a {
+			active++
+		}
+	}
+	set, _ := NewCardSet(NewShoe(v.Settings.Decks(active)))
+	for _, c := range v.Hand {
+		set.Remove(c)
+	}
+	for _, c := range v.Discard {
+		set.Remove(c)
+	}
+	return Shoe(set.Hand())
+}
+
+// Analyze works out, for each card in the player's hand, the resulting count
+// and the probability that the next player would be forced to bust.
+// The next player is forced to bust if every card in their hand would bust the new count.
+// Their hand is assumed to be a uniformly random selection of the unseen cards.
+func Analyze(v NNView) []CardHint {
+	unseen := v.Unseen()
+	hints := make([]CardHint, len(v.Hand))
+	for i, c := range v.Hand {
+		count, err := v.Outcome(c)
+		h := CardHint{Index: i, Card: c, Count: count, Safe: err == nil && count <= v.Settings.MaxCount}
+		if h.Safe {
+			after := v
+			after.Count = count
+			if c.rank == v.Settings.WildCards.Reverse {
+				after.Direction *= -1
+			}
+			h.Next = after.nextSeat()
+			if 0 <= h.Next && h.Next < len(v.HandSizes) {
+				h.BustsNext = after.forcedBust(unseen, v.HandSizes[h.Next])
+			}
+		} else {
+			h.Next = NoSeat
+		}
+		hints[i] = h
+	}
+	return hints
+}
+
+// nextSeat returns the seat of the next active player after the viewer, in the direction of play.
+func (v NNView) nextSeat() int {
+	n := len(v.Active)
+	for step := 1; step <= n; step++ {
+		seat := ((v.Seat+step*v.Direction)%n + n) % n
+		if v.Active[seat] && seat != v.Seat {
+			return seat
+		}
+	}
+	return NoSeat
+}
+
+// forcedBust returns the probability that a hand of the given size, drawn from the unseen cards,
+// holds only cards that bust the count.
+func (v NNView) forcedBust(unseen Shoe, size int) float64 {
+	busting := 0
+	for _, c := range unseen {
+		if !v.Safe(c) {
+			busting++
+		}
+	}
+	if size <= 0 || size > len(unseen) {
+		return 0
+	}
+	p := 1.0
+	for i := 0; i < size; i++ {
+		p *= float64(busting-i) / float64(len(unseen)-i)
+		if p <= 0 {
+			return 0
+		}
+	}
+	return p
+}
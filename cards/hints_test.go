@@ -0,0 +1,54 @@
+package cards
+
+import (
+	"math"
+	"shuffle/utils"
+	"testing"
+)
+
+func TestUnseen(t *testing.T) {
+	v := NNView{
+		Hand:     Hand{NewCard(Nine, Clubs)},
+		Discard:  Shoe{NewCard(Two, Hearts), NewCard(Three, Hearts)},
+		Active:   []bool{true, true},
+		Settings: *NNDefaultSettings,
+	}
+	unseen := v.Unseen()
+	utils.Error(t, len(unseen), CardsPerDeck-3, "unseen cards")
+	set, _ := NewCardSet(unseen)
+	utils.Error(t, set.Contains(NewCard(Two, Hearts)), false, "discarded card unseen")
+	utils.Error(t, set.Contains(NewCard(Nine, Clubs)), false, "held card unseen")
+}
+
+func TestAnalyze(t *testing.T) {
+	v := NNView{
+		Seat:      0,
+		Hand:      Hand{NewCard(Nine, Clubs), NewCard(Two, Hearts), NewCard(Queen, Spades), NewCard(Four, Hearts)},
+		Count:     90,
+		Direction: 1,
+		HandSizes: []int{4, 3, 2},
+		Active:    []bool{true, true, true},
+		Settings:  *NNDefaultSettings,
+	}
+	hints := Analyze(v)
+	utils.Fatal(t, len(hints), 4, "hints")
+
+	nine := hints[0]
+	utils.Error(t, nine.Count, 99, "count after nine")
+	utils.Error(t, nine.Safe, true, "nine safe")
+	utils.Error(t, nine.Next, 1, "next seat after nine")
+	// At 99, only 4s, 9s, 10s and Ks are safe: 14 of the 48 unseen cards.
+	want := 34.0 / 48 * 33 / 47 * 32 / 46
+	utils.Error(t, math.Abs(nine.BustsNext-want) < 1e-9, true, "probability of a forced bust after nine")
+
+	utils.Error(t, hints[1].Count, 92, "count after two")
+	// At 92, only 8s, Js and Qs bust: 11 of the 48 unseen cards.
+	want = 11.0 / 48 * 10 / 47 * 9 / 46
+	utils.Error(t, math.Abs(hints[1].BustsNext-want) < 1e-9, true, "probability of a forced bust after two")
+
+	utils.Error(t, hints[2].Safe, false, "queen safe at 90")
+	utils.Error(t, hints[2].Next, NoSeat, "next seat after busting")
+
+	four := hints[3]
+	utils.Error(t, four.Next, 2, "next seat after reversing")
+}
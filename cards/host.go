@@ -0,0 +1,347 @@
+package cards
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// errNotHost is returned when someone other than the host tries to look after the table.
+var errNotHost = errors.New("only the host can do that")
+
+// Pause stops play until the game is resumed: no card may be played, and the turn timer is stopped.
+// It returns an error if no game is in progress, or it is already paused.
+func (mgr *NNGameManager) Pause() error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if mgr.paused {
+		return errors.New("the game is already paused")
+	}
+	mgr.paused = true
+	mgr.pausedAt = mgr.now()
+	mgr.emit(EventPaused, NoSeat, nil)
+	return nil
+}
+
+// Resume lets play go on after a pause. The current player has as long left on their turn as they had when it was paused.
+// It returns an error if no game is in progress, or it is not paused.
+func (mgr *NNGameManager) Resume() error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if !mgr.paused {
+		return errors.New("the game is not paused")
+	}
+	mgr.paused = false
+	mgr.turnStart = mgr.turnStart.Add(mgr.now().Sub(mgr.pausedAt))
+	mgr.emit(EventResumed, NoSeat, nil)
+	return nil
+}
+
+// Paused returns true while the game is paused.
+func (mgr *NNGameManager) Paused() bool {
+	return mgr.paused
+}
+
+// Kick removes the player in the given seat from the game. With robot true, a robot takes over their hand
+// and plays on in their place; otherwise they are eliminated, and play passes on if it was their turn.
+// It returns an error if nobody in that seat is still in the game.
+func (mgr *NNGameManager) Kick(seat int, robot bool) error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if !mgr.table.Active(seat) {
+		return errors.New("nobody in that seat is still in the game")
+	}
+	p := mgr.players[seat].player
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventKicked, Seat: seat, Count: mgr.count, Name: p.Name()})
+	if robot {
+		mgr.takeOver(p)
+		return nil
+	}
+	mgr.undo = nil
+	mgr.eliminate(seat)
+	if mgr.decided() {
+		return nil
+	}
+	if mgr.table.CurrSeat() == seat {
+		mgr.table.Advance()
+		mgr.beginTurn()
+	}
+	return nil
+}
+
+// AddRobot seats a robot, which plays cautiously, when the next round is dealt.
+// It begins with as many lives as everyone began the game with.
+// It returns an error if no game is in progress.
+func (mgr *NNGameManager) AddRobot() error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	}
+	mgr.reseat++
+	return nil
+}
+
+// RemoveRobot takes the last robot seated out of the game when the next round is dealt.
+// It returns an error if no game is in progress, there is no robot to remove,
+// or removing it would leave fewer than two players.
+func (mgr *NNGameManager) RemoveRobot() error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if len(mgr.robots())+mgr.reseat <= 0 {
+		return errors.New("there are no robots to remove")
+	} else if len(mgr.table.Remaining())+mgr.reseat <= 2 {
+		return errors.New("a game needs at least two players")
+	}
+	mgr.reseat--
+	return nil
+}
+
+// ChangeRules changes the rules the game is played by. The new rules take effect when the next round is dealt,
+// and players keep the lives they have left. It returns an error if no game is in progress,
+// or the new rules change the number of lives everyone begins with.
+func (mgr *NNGameManager) ChangeRules(settings *NNGameSettings) error {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if settings.LivesPerPlayer != mgr.settings.LivesPerPlayer {
+		return errors.New("the number of lives cannot change once the game has begun")
+	}
+	rules := *settings
+	mgr.rules = &rules
+	return nil
+}
+
+// applyRules puts the rules asked for since the last round was dealt into effect.
+func (mgr *NNGameManager) applyRules() {
+	if mgr.rules == nil {
+		return
+	}
+	mgr.setSettings(mgr.rules)
+	mgr.rules = nil
+	mgr.emit(EventRulesChanged, NoSeat, nil)
+}
+
+// robots returns the seats of the robots still in the game, in seating order.
+func (mgr *NNGameManager) robots() []int {
+	var seats []int
+	for _, p := range mgr.table.Remaining() {
+		if mgr.players[p.ID()].player.Robot() {
+			seats = append(seats, p.ID())
+		}
+	}
+	return seats
+}
+
+// seatRobots seats and unseats the robots asked for since the last round was dealt.
+// Robots are unseated last seated first, for as long as at least two players would remain.
+func (mgr *NNGameManager) seatRobots() {
+	for ; mgr.reseat > 0; mgr.reseat-- {
+		p := NewNNRobot(mgr.robotName(), NewCautiousStrategy())
+		id := mgr.table.Sit(p, mgr)
+		mgr.players[id] = &NNPlayerStats{player: p, lives: mgr.settings.LivesPerPlayer}
+		mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventRobotAdded, Seat: id, Count: mgr.count,
+			Lives: mgr.settings.LivesPerPlayer, Names: tableNames(mgr.table), Name: p.Name()})
+	}
+	for ; mgr.reseat < 0; mgr.reseat++ {
+		robots := mgr.robots()
+		if len(robots) == 0 || len(mgr.table.Remaining()) <= 2 {
+			mgr.reseat = 0
+			return
+		}
+		id := robots[len(robots)-1]
+		mgr.table.Eliminate(id)
+		mgr.players[id].player.ReplaceHand(Hand{})
+		mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventRobotRemoved, Seat: id, Count: mgr.count, Name: mgr.players[id].player.Name()})
+	}
+}
+
+// robotName returns a name for a new robot that nobody at the table has, e.g. "Robot 3".
+func (mgr *NNGameManager) robotName() string {
+	taken := make(map[string]bool)
+	for _, name := range tableNames(mgr.table) {
+		taken[name] = true
+	}
+	for n := 1; ; n++ {
+		if name := fmt.Sprintf("Robot %d", n); !taken[name] {
+			return name
+		}
+	}
+}
+
+// isHost returns true if the person in the seat looks after the table.
+func (t *LobbyTable) isHost(s *Seat) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.host == s
+}
+
+// seated returns the seat of the person at the table with the given name, ignoring case,
+// or nil if nobody called that still has a seat.
+func (t *LobbyTable) seated(name string) *Seat {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.seats {
+		if !s.replaced && strings.EqualFold(s.Name(), name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// passHost makes the next person still seated the host, if the seat that was given up was the host's.
+// The caller must hold the table's lock.
+func (t *LobbyTable) passHost(s *Seat) {
+	if t.host != s {
+		return
+	}
+	t.host = nil
+	for _, seated := range t.seats {
+		if !seated.replaced {
+			t.host = seated
+			return
+		}
+	}
+}
+
+// resumeUnhosted resumes a paused game once nobody is left to look after the table, so that the robots can play it out.
+func (t *LobbyTable) resumeUnhosted() {
+	t.mu.Lock()
+	unhosted := t.host == nil
+	t.mu.Unlock()
+	if unhosted && t.mgr.Paused() {
+		t.mgr.Resume()
+	}
+}
+
+// countRobots keeps the table's seats up to date with the robots the host seats and unseats.
+// Robots seated during the game add a seat to the table, and those unseated take one away.
+func (t *LobbyTable) countRobots(e Event) {
+	if e.Type != EventRobotAdded && e.Type != EventRobotRemoved {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	change := 1
+	if e.Type == EventRobotRemoved {
+		change = -1
+	}
+	t.robots += change
+	if t.playing {
+		t.opts.Seats += change
+	}
+}
+
+// Pause pauses the game, or with on false resumes it. Only the host may pause the game, once it has started.
+func (s *Seat) Pause(on bool) (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if !s.table.isHost(s) {
+			err = errNotHost
+		} else if !mgr.Playing() {
+			err = errors.New("the game has not started")
+		} else if on {
+			err = mgr.Pause()
+		} else {
+			err = mgr.Resume()
+		}
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// Kick removes the person with the given name from the table. Before the game begins, their seat is given up;
+// once it has started, a robot takes over their hand if robot is true, and otherwise they are eliminated.
+// Only the host may kick people, and they cannot kick themselves.
+func (s *Seat) Kick(name string, robot bool) (err error) {
+	var target *Seat
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		t := s.table
+		target = t.seated(name)
+		if !t.isHost(s) {
+			err = errNotHost
+		} else if target == nil {
+			err = errors.Errorf("nobody called %s is at the table", name)
+		} else if target == s {
+			err = errors.New("the host cannot kick themselves")
+		} else if t.unseat(target) {
+			t.emit(mgr, EventKicked, target, "")
+		} else if err = mgr.Kick(target.player.ID(), robot); err == nil {
+			target.replaced = true
+		}
+	})
+	if doErr != nil {
+		return doErr
+	} else if err != nil {
+		return err
+	}
+	s.table.lobby.endSession(target)
+	return nil
+}
+
+// AddRobot seats a robot at the table. Before the game begins, the robot fills an empty seat,
+// and the game begins if it was the last one; once it has started, the robot joins when the next round is dealt.
+// Only the host may seat robots.
+func (s *Seat) AddRobot() (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		t := s.table
+		if !t.isHost(s) {
+			err = errNotHost
+		} else if mgr.Playing() {
+			err = mgr.AddRobot()
+		} else if info := t.Info(); len(info.Players)+info.Robots >= info.Seats {
+			err = errors.New("the table is full")
+		} else {
+			mgr.Events().Emit(Event{Game: info.Settings.Game(), Type: EventRobotAdded, Seat: NoSeat})
+		}
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// RemoveRobot unseats a robot from the table. Before the game begins, its seat is left empty for someone to take;
+// once it has started, the last robot seated leaves when the next round is dealt.
+// Only the host may unseat robots.
+func (s *Seat) RemoveRobot() (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		t := s.table
+		if !t.isHost(s) {
+			err = errNotHost
+		} else if mgr.Playing() {
+			err = mgr.RemoveRobot()
+		} else if info := t.Info(); info.Robots == 0 {
+			err = errors.New("there are no robots at the table")
+		} else {
+			mgr.Events().Emit(Event{Game: info.Settings.Game(), Type: EventRobotRemoved, Seat: NoSeat})
+		}
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// SetRules changes the rules the table's game is played by. Only the host may change them.
+// Once the game has begun, the new rules take effect when the next round is dealt, and the number of lives cannot change.
+func (s *Seat) SetRules(settings NNGameSettings) (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		t := s.table
+		if !t.isHost(s) {
+			err = errNotHost
+			return
+		} else if mgr.Playing() {
+			if err = mgr.ChangeRules(&settings); err != nil {
+				return
+			}
+		}
+		t.mu.Lock()
+		t.opts.Settings = &settings
+		t.mu.Unlock()
+		t.emit(mgr, EventRulesChanged, s, "")
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+package cards
+
+import (
+	"fmt"
+	"shuffle/utils"
+	"testing"
+	"time"
+)
+
+func TestPause(t *testing.T) {
+	mgr, players, clock := newTimedGame(TimeoutSafestCard)
+	utils.Fatal(t, mgr.Pause(), nil)
+	utils.Error(t, mgr.Paused(), true)
+	utils.Error(t, lastEvent(mgr), EventPaused)
+	utils.Error(t, mgr.Pause() != nil, true, "pausing twice")
+	err := players[0].Play(Hand{NewCard(Two, Clubs)})
+	utils.Error(t, fmt.Sprint(err), "the game is paused")
+
+	clock.Advance(time.Minute)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, lastEvent(mgr), EventPaused, "the turn timer is stopped")
+	utils.Fatal(t, mgr.Resume(), nil)
+	utils.Error(t, lastEvent(mgr), EventResumed)
+	utils.Error(t, mgr.Resume() != nil, true, "resuming twice")
+	deadline, _ := mgr.TurnDeadline()
+	utils.Error(t, deadline, clock.now.Add(30*time.Second), "the time left on the turn")
+	utils.Error(t, players[0].Play(Hand{NewCard(Two, Clubs)}), nil)
+}
+
+func TestKick(t *testing.T) {
+	mgr, players := newTestGame("Alice", "Bob", "Charlie")
+	utils.Fatal(t, mgr.Kick(0, false), nil)
+	utils.Error(t, mgr.Table().Active(0), false, "kicked out")
+	utils.Error(t, mgr.CurrPlayer().ID(), 1, "play passes on")
+	history := mgr.Events().History()
+	utils.Error(t, history[len(history)-2].Name, "Alice")
+	utils.Error(t, history[len(history)-2].Type, EventKicked)
+	utils.Error(t, history[len(history)-1].Type, EventEliminated)
+	utils.Error(t, mgr.Kick(0, false) != nil, true, "kicking someone out of the game")
+
+	utils.Fatal(t, mgr.Kick(2, true), nil)
+	utils.Error(t, players[2].Robot(), true, "a robot plays on")
+	utils.Error(t, lastEvent(mgr), EventReplaced)
+	utils.Fatal(t, mgr.Kick(1, false), nil)
+	utils.Error(t, mgr.Playing(), false)
+	winner, _ := mgr.Winner()
+	utils.Error(t, winner, Player(players[2]))
+}
+
+func TestSeatRobots(t *testing.T) {
+	mgr, players := newTestGame("Alice", "Bob")
+	alice, bob := players[0], players[1]
+	utils.Fatal(t, mgr.AddRobot(), nil)
+	utils.Fatal(t, mgr.AddRobot(), nil)
+	utils.Error(t, mgr.Table().Size(), 2, "robots wait for the next round")
+
+	mgr.count = 95
+	alice.ReplaceHand(Hand{NewCard(Queen, Hearts)})
+	utils.Fatal(t, alice.Play(Hand{NewCard(Queen, Hearts)}), nil)
+	utils.Error(t, tableNames(mgr.Table()), []string{"Alice", "Bob", "Robot 1", "Robot 2"})
+	utils.Error(t, mgr.Lives(3), NNDefaultSettings.LivesPerPlayer)
+	v := mgr.View(3)
+	utils.Error(t, len(v.Hand), NNDefaultSettings.CardsPerPlayer, "robots are dealt in")
+	var types []EventType
+	for _, e := range mgr.Events().History()[len(mgr.Events().History())-5:] {
+		types = append(types, e.Type)
+	}
+	utils.Error(t, types, []EventType{EventBusted, EventLifeLost, EventRobotAdded, EventRobotAdded, EventDealt})
+
+	utils.Fatal(t, mgr.RemoveRobot(), nil)
+	utils.Fatal(t, mgr.RemoveRobot(), nil)
+	utils.Error(t, fmt.Sprint(mgr.RemoveRobot()), "there are no robots to remove")
+	mgr.count = 95
+	bob.ReplaceHand(Hand{NewCard(Queen, Hearts)})
+	mgr.Table().SetCurrSeat(bob.ID())
+	utils.Fatal(t, bob.Play(Hand{NewCard(Queen, Hearts)}), nil)
+	utils.Error(t, len(mgr.Table().Remaining()), 2, "robots removed")
+	utils.Error(t, mgr.Table().Active(2), false)
+	utils.Error(t, mgr.Table().Active(3), false)
+
+	mgr = new(NNGameManager)
+	mgr.StartGame([]*NNPlayer{NewNNPlayer("Alice")}, 1, nil)
+	utils.Error(t, fmt.Sprint(mgr.RemoveRobot()), "a game needs at least two players")
+}
+
+func TestChangeRules(t *testing.T) {
+	mgr, players := newTestGame("Alice", "Bob")
+	settings := *NNDefaultSettings
+	settings.MaxCount = 50
+	utils.Fatal(t, mgr.ChangeRules(&settings), nil)
+	utils.Error(t, mgr.settings.MaxCount, 99, "the rules change when the next round is dealt")
+
+	mgr.count = 95
+	players[0].ReplaceHand(Hand{NewCard(Queen, Hearts)})
+	utils.Fatal(t, players[0].Play(Hand{NewCard(Queen, Hearts)}), nil)
+	utils.Error(t, mgr.settings.MaxCount, 50, "the rules of the new round")
+	utils.Error(t, mgr.Lives(0), NNDefaultSettings.LivesPerPlayer-1, "lives are kept")
+	history := mgr.Events().History()
+	utils.Error(t, history[len(history)-2].Type, EventRulesChanged)
+	utils.Error(t, lastEvent(mgr), EventDealt)
+
+	settings.LivesPerPlayer = 5
+	utils.Error(t, fmt.Sprint(mgr.ChangeRules(&settings)), "the number of lives cannot change once the game has begun")
+	utils.Fatal(t, mgr.AddRobot(), nil)
+	mgr.count = 45
+	players[1].ReplaceHand(Hand{NewCard(Queen, Hearts)})
+	utils.Fatal(t, players[1].Play(Hand{NewCard(Queen, Hearts)}), nil)
+	utils.Error(t, mgr.Lives(2), NNDefaultSettings.LivesPerPlayer, "robots begin with as many lives as everyone began with")
+
+	mgr.EndGame()
+	utils.Error(t, mgr.ChangeRules(&settings) != nil, true, "changing the rules with no game in progress")
+}
+
+func TestPausedWithoutHost(t *testing.T) {
+	cfg := testLobby
+	cfg.Grace = time.Minute
+	cfg.RobotDelay = time.Millisecond
+	lobby := NewLobby(cfg)
+	defer lobby.Close()
+	table, err := lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	clock := &fakeClock{now: time.Date(2021, time.December, 25, 9, 0, 0, 0, time.UTC)}
+	table.Do(func(mgr *NNGameManager) { mgr.SetClock(clock) })
+	alice, err := lobby.Join(table.ID(), "Alice")
+	utils.Fatal(t, err, nil)
+	utils.Fatal(t, alice.AddRobot(), nil)
+	waitFor(t, table, EventGameCreated)
+
+	utils.Fatal(t, alice.Pause(true), nil)
+	utils.Fatal(t, alice.Disconnect(0), nil)
+	clock.Advance(cfg.Grace)
+	waitFor(t, table, EventResumed)
+	waitClosed(t, table)
+}
+
+func TestHostControls(t *testing.T) {
+	lobby := NewLobby(testLobby)
+	defer lobby.Close()
+	table, err := lobby.CreateTable(TableOptions{Seats: 3})
+	utils.Fatal(t, err, nil)
+	alice, err := lobby.Join(table.ID(), "Alice")
+	utils.Fatal(t, err, nil)
+	bob, err := lobby.Join(table.ID(), "Bob")
+	utils.Fatal(t, err, nil)
+
+	settings := *NNDefaultSettings
+	settings.LivesPerPlayer = 1
+	utils.Error(t, bob.SetRules(settings), errNotHost)
+	utils.Fatal(t, alice.SetRules(settings), nil)
+	utils.Error(t, table.Info().Settings, settings)
+	utils.Error(t, bob.Kick("alice", false), errNotHost)
+	utils.Error(t, fmt.Sprint(alice.Kick("Alice", false)), "the host cannot kick themselves")
+	utils.Fatal(t, alice.Kick("bob", false), nil)
+	utils.Error(t, table.Info().Players, []string{"Alice"})
+	utils.Error(t, fmt.Sprint(bob.Chat("hi")), "you no longer have a seat at the table")
+	_, _, err = lobby.Reconnect(bob.Token())
+	utils.Error(t, err != nil, true, "the kicked seat is not held")
+
+	utils.Error(t, fmt.Sprint(alice.RemoveRobot()), "there are no robots at the table")
+	utils.Fatal(t, alice.AddRobot(), nil)
+	utils.Fatal(t, alice.RemoveRobot(), nil)
+	utils.Fatal(t, alice.AddRobot(), nil)
+	utils.Error(t, table.Info().Robots, 1)
+	utils.Error(t, fmt.Sprint(alice.Pause(true)), "the game has not started")
+	charlie, err := lobby.Join(table.ID(), "Charlie")
+	utils.Fatal(t, err, nil)
+	waitFor(t, table, EventGameCreated)
+	settings.Undo = true
+	utils.Fatal(t, alice.SetRules(settings), nil)
+	utils.Error(t, table.Info().Settings, settings, "the rules for the next round")
+
+	utils.Error(t, charlie.Pause(true), errNotHost)
+	utils.Fatal(t, alice.Pause(true), nil)
+	utils.Error(t, fmt.Sprint(charlie.Play(0)), "the game is paused")
+	utils.Fatal(t, alice.Pause(false), nil)
+	utils.Fatal(t, alice.Kick("Charlie", true), nil)
+	utils.Error(t, waitFor(t, table, EventReplaced).Seat, 1)
+	utils.Error(t, charlie.Connected(), false)
+	playOut(t, alice)
+}
@@ -0,0 +1,646 @@
+package cards
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LobbyConfig holds the settings for a Lobby.
+type LobbyConfig struct {
+	Seats      int             // the number of seats at tables created by quick-match
+	RobotWait  time.Duration   // how long quick-match tables wait for people before their empty seats are filled by robots
+	RobotDelay time.Duration   // how long robots pause before playing, so that people can follow the game
+	Grace      time.Duration   // how long the seats of people who lose their connection are held for them
+	Idle       time.Duration   // how long a table with nobody seated waits for someone to sit down before it closes, or 0 to wait forever
+	Settings   *NNGameSettings // the rules of quick-match tables and tables created without rules of their own, or nil for house rules
+	Chat       ChatConfig      // the limits on what people at each table may say to each other
+	Log        *EventLog       // records the Events of every table, if set
+}
+
+// LobbyDefaults are the settings for a Lobby of networked games of 99.
+var LobbyDefaults = LobbyConfig{
+	Seats:      4,
+	RobotWait:  30 * time.Second,
+	RobotDelay: time.Second,
+	Grace:      2 * time.Minute,
+	Idle:       5 * time.Minute,
+	Chat:       ChatDefaults,
+}
+
+// MaxSeats is the most seats a table in a Lobby may have.
+const MaxSeats = 40
+
+// joinCodeAlphabet holds the characters join codes are made of, leaving out those that are easily confused, such as O and 0.
+const joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// joinCodeLength is the number of characters in a join code.
+const joinCodeLength = 6
+
+// timerTick is how often tables check whether the current player has run out of time,
+// and whether anyone who lost their connection has run out of time to reconnect.
+const timerTick = 100 * time.Millisecond
+
+// errTableClosed is returned for any action at a table whose game has ended.
+var errTableClosed = errors.New("the table has closed")
+
+// TableOptions describes a table to create in a Lobby.
+type TableOptions struct {
+	Seats     int             // the number of seats at the table, for people and robots
+	Settings  *NNGameSettings // the rules of the game, or nil for house rules
+	Private   bool            // whether the table is left out of the list of tables, so that it can only be joined with its join code
+	RobotWait time.Duration   // how long to wait for people before the empty seats are filled by robots, or 0 to wait for people forever
+}
+
+// TableInfo describes a table in a Lobby, as it was when it was listed.
+type TableInfo struct {
+	ID       string
+	Settings NNGameSettings
+	Seats    int
+	Players  []string // the names of the people seated at the table, in order
+	Host     string   // the name of the person who looks after the table, if anyone is seated
+	Robots   int      // the number of seats filled by robots
+	Playing  bool     // whether the game has started
+}
+
+// Open returns true if the table has an empty seat and its game has not started.
+func (info TableInfo) Open() bool {
+	return !info.Playing && len(info.Players)+info.Robots < info.Seats
+}
+
+// Lobby gathers players into games of 99 at tables run in-process.
+// Tables are listed publicly, or are private and joined with a join code.
+// Players may also queue for a quick match, which seats them at an open table with their preferred rules.
+// Each table's game is run by its own NNGameManager on a goroutine of its own,
+// which stops and removes the table from the lobby once the game is over.
+// A Lobby is safe for concurrent use.
+type Lobby struct {
+	cfg      LobbyConfig
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mu       sync.Mutex
+	tables   map[string]*LobbyTable // by ID
+	codes    map[string]*LobbyTable // private tables, by join code
+	sessions map[string]*Seat       // by session token
+	nextID   int
+	wg       sync.WaitGroup
+}
+
+// NewLobby creates an empty Lobby.
+func NewLobby(cfg LobbyConfig) *Lobby {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lobby{
+		cfg:      cfg,
+		ctx:      ctx,
+		cancel:   cancel,
+		tables:   make(map[string]*LobbyTable),
+		codes:    make(map[string]*LobbyTable),
+		sessions: make(map[string]*Seat),
+	}
+}
+
+// CreateTable sets up a new table, which waits for players to join.
+// Its game begins as soon as every seat is filled.
+// It returns an error if the table would have too few or too many seats.
+func (l *Lobby) CreateTable(opts TableOptions) (*LobbyTable, error) {
+	if opts.Seats < 2 || opts.Seats > MaxSeats {
+		return nil, errors.Errorf("tables have 2-%d seats", MaxSeats)
+	}
+	if opts.Settings == nil {
+		opts.Settings = l.settings()
+	}
+	settings := *opts.Settings
+	opts.Settings = &settings
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.ctx.Err() != nil {
+		return nil, errors.New("the lobby has closed")
+	}
+	l.nextID++
+	t := &LobbyTable{
+		n:        l.nextID,
+		id:       fmt.Sprintf("t%d", l.nextID),
+		opts:     opts,
+		lobby:    l,
+		mgr:      new(NNGameManager),
+		commands: make(chan func()),
+		done:     make(chan struct{}),
+	}
+	t.mgr.SetEvents(NewEventStream())
+	t.mgr.Events().Subscribe(t.countRobots)
+	if l.cfg.Log != nil {
+		t.mgr.Events().Subscribe(func(e Event) {
+			e.Table = t.id
+			l.cfg.Log.Record(e)
+		})
+	}
+	if opts.Private {
+		for t.code == "" || l.codes[t.code] != nil {
+			code, err := newJoinCode()
+			if err != nil {
+				return nil, err
+			}
+			t.code = code
+		}
+		l.codes[t.code] = t
+	}
+	l.tables[t.id] = t
+	l.wg.Add(1)
+	go t.run(l.ctx)
+	return t, nil
+}
+
+// newJoinCode generates a random join code for a private table.
+func newJoinCode() (string, error) {
+	b := make([]byte, joinCodeLength)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", errors.Wrap(err, "cannot generate a join code")
+	}
+	for i := range b {
+		b[i] = joinCodeAlphabet[int(b[i])%len(joinCodeAlphabet)]
+	}
+	return string(b), nil
+}
+
+// Tables lists the public tables in the lobby, including those whose games have started, oldest first.
+func (l *Lobby) Tables() []TableInfo {
+	l.mu.Lock()
+	var tables []*LobbyTable
+	for _, t := range l.tables {
+		if t.code == "" {
+			tables = append(tables, t)
+		}
+	}
+	l.mu.Unlock()
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].n < tables[j].n
+	})
+	infos := make([]TableInfo, len(tables))
+	for i, t := range tables {
+		infos[i] = t.Info()
+	}
+	return infos
+}
+
+// Table returns the table with the given ID or join code, if it is still open.
+// Private tables can only be found by their join code.
+func (l *Lobby) Table(key string) (*LobbyTable, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.codes[key]; ok {
+		return t, true
+	}
+	if t, ok := l.tables[key]; ok && t.code == "" {
+		return t, true
+	}
+	return nil, false
+}
+
+// Join seats a player at the table with the given ID or join code.
+// It returns an error if there is no such table, it is full, its game has started,
+// or someone with the same name is already seated there.
+func (l *Lobby) Join(key, name string) (*Seat, error) {
+	t, ok := l.Table(key)
+	if !ok {
+		return nil, errors.Errorf("no table %q", key)
+	}
+	return t.sit(name)
+}
+
+// settings returns the rules of tables created in the lobby without rules of their own.
+func (l *Lobby) settings() *NNGameSettings {
+	if l.cfg.Settings != nil {
+		return l.cfg.Settings
+	}
+	return NNDefaultSettings
+}
+
+// QuickMatch seats a player at the oldest open public table with their preferred rules,
+// or at a new table if there is none. Quick-match tables fill their empty seats with robots
+// once they have waited long enough for people.
+func (l *Lobby) QuickMatch(name string, settings *NNGameSettings) (*Seat, error) {
+	if settings == nil {
+		settings = l.settings()
+	}
+	for _, info := range l.Tables() {
+		if info.Open() && info.Settings == *settings {
+			if t, ok := l.Table(info.ID); ok {
+				if seat, err := t.sit(name); err == nil {
+					return seat, nil
+				}
+			}
+		}
+	}
+	t, err := l.CreateTable(TableOptions{Seats: l.cfg.Seats, Settings: settings, RobotWait: l.cfg.RobotWait})
+	if err != nil {
+		return nil, err
+	}
+	return t.sit(name)
+}
+
+// Close ends every game in the lobby, and waits for their tables to close.
+func (l *Lobby) Close() {
+	l.mu.Lock()
+	l.cancel()
+	l.mu.Unlock()
+	l.wg.Wait()
+}
+
+// remove takes a table out of the lobby.
+func (l *Lobby) remove(t *LobbyTable) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.tables, t.id)
+	if t.code != "" {
+		delete(l.codes, t.code)
+	}
+	for token, seat := range l.sessions {
+		if seat.table == t {
+			delete(l.sessions, token)
+		}
+	}
+}
+
+// LobbyTable is a table in a Lobby, whose game of 99 is run by an NNGameManager on a goroutine of its own.
+// Everything that happens at the table is done on that goroutine, one thing at a time, so that
+// players who are connected concurrently always see a consistent game.
+type LobbyTable struct {
+	n         int // the order the table was created in
+	id        string
+	code      string
+	opts      TableOptions
+	lobby     *Lobby
+	mgr       *NNGameManager
+	commands  chan func()
+	done      chan struct{}
+	mu        sync.Mutex // guards seats, host, robots and playing, which are listed from other goroutines
+	seats     []*Seat    // the people seated at the table, in order
+	host      *Seat      // the person who looks after the table: the first to sit down, or the next if they leave or are replaced
+	robots    int
+	playing   bool
+	announced int // the last turn the player whose turn it was was told about
+}
+
+// ID returns the table's ID, which public tables are joined with.
+func (t *LobbyTable) ID() string {
+	return t.id
+}
+
+// Code returns the join code of a private table, or the empty string for a public table.
+func (t *LobbyTable) Code() string {
+	return t.code
+}
+
+// Info describes the table as it is now.
+func (t *LobbyTable) Info() TableInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info := TableInfo{
+		ID:       t.id,
+		Settings: *t.opts.Settings,
+		Seats:    t.opts.Seats,
+		Robots:   t.robots,
+		Playing:  t.playing,
+	}
+	for _, s := range t.seats {
+		info.Players = append(info.Players, s.Name())
+	}
+	if t.host != nil {
+		info.Host = t.host.Name()
+	}
+	return info
+}
+
+// Events returns the stream of Events of the table's game.
+func (t *LobbyTable) Events() *EventStream {
+	return t.mgr.Events()
+}
+
+// Spectate lets someone watch the table's game, as Spectate does.
+func (t *LobbyTable) Spectate(w io.Writer, cfg SpectatorConfig) *Spectator {
+	return Spectate(t.mgr, w, cfg)
+}
+
+// Done returns a channel that is closed once the table has closed, after its game ends.
+func (t *LobbyTable) Done() <-chan struct{} {
+	return t.done
+}
+
+// Do runs the function on the table's goroutine, with the manager of its game, and waits for it to finish.
+// It must not be called from an Event listener, which already runs on the table's goroutine.
+// It returns an error if the table has closed.
+func (t *LobbyTable) Do(f func(mgr *NNGameManager)) error {
+	ran := make(chan struct{})
+	select {
+	case t.commands <- func() { f(t.mgr); close(ran) }:
+		<-ran
+		return nil
+	case <-t.done:
+		return errTableClosed
+	}
+}
+
+// sit seats a person at the table, and begins the game if they fill the last seat.
+// The seat is given a session token, with which the person can reconnect to it.
+func (t *LobbyTable) sit(name string) (seat *Seat, err error) {
+	if name = strings.TrimSpace(stripControl(name)); name == "" {
+		return nil, errors.New("names need at least one printable character")
+	}
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, err
+	}
+	doErr := t.Do(func(mgr *NNGameManager) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.playing {
+			err = errors.New("the game has already started")
+			return
+		} else if len(t.seats)+t.robots >= t.opts.Seats {
+			err = errors.New("the table is full")
+			return
+		}
+		for _, s := range t.seats {
+			if s.Name() == name {
+				err = errors.Errorf("%s is already at the table", name)
+				return
+			}
+		}
+		seat = &Seat{table: t, player: NewNNPlayer(name), token: token, turn: make(chan struct{}, 1), superseded: make(chan struct{})}
+		t.seats = append(t.seats, seat)
+		if t.host == nil {
+			t.host = seat
+		}
+	})
+	if doErr != nil {
+		return nil, doErr
+	} else if err != nil {
+		return nil, err
+	}
+	t.lobby.mu.Lock()
+	t.lobby.sessions[token] = seat
+	t.lobby.mu.Unlock()
+	return seat, nil
+}
+
+// waiting returns true if anyone is seated at the table, waiting for the game to begin.
+func (t *LobbyTable) waiting() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.playing && len(t.seats) > 0
+}
+
+// start begins the game once every seat is filled, if it has not begun already.
+func (t *LobbyTable) start() {
+	t.mu.Lock()
+	if t.playing || len(t.seats)+t.robots < t.opts.Seats {
+		t.mu.Unlock()
+		return
+	}
+	t.playing = true
+	players := make([]*NNPlayer, len(t.seats))
+	for i, s := range t.seats {
+		players[i] = s.player
+	}
+	robots := t.robots
+	t.mu.Unlock()
+	t.mgr.StartGame(players, robots, t.opts.Settings)
+}
+
+// fillWithRobots fills the empty seats with robots, if anyone is waiting to play.
+func (t *LobbyTable) fillWithRobots() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.playing && len(t.seats) > 0 {
+		t.robots = t.opts.Seats - len(t.seats)
+	}
+}
+
+// robotToPlay returns true if it is a robot's turn, and the game is not paused.
+func (t *LobbyTable) robotToPlay() bool {
+	if !t.mgr.Playing() || t.mgr.Paused() {
+		return false
+	}
+	p, ok := t.mgr.CurrPlayer().(*NNPlayer)
+	return ok && p.Robot()
+}
+
+// announceTurn tells the person whose turn it is that it is their turn, once per turn.
+func (t *LobbyTable) announceTurn() {
+	if !t.mgr.Playing() || t.mgr.turn == t.announced {
+		return
+	}
+	t.announced = t.mgr.turn
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.seats {
+		if s.player.ID() == t.mgr.table.CurrSeat() && !s.player.Robot() {
+			select {
+			case s.turn <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// empty returns true if nobody is seated at the table, and its game has not begun.
+func (t *LobbyTable) empty() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.playing && len(t.seats) == 0
+}
+
+// over returns true once the table's game has been played to the end.
+func (t *LobbyTable) over() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.playing && !t.mgr.Playing()
+}
+
+// run runs the table until its game is over, it has stood empty for the lobby's idle period, or the context is cancelled:
+// it seats players, begins the game once the table is full, plays the robots' turns and enforces the turn timer.
+func (t *LobbyTable) run(ctx context.Context) {
+	defer t.lobby.wg.Done()
+	defer close(t.done)
+	defer t.lobby.remove(t)
+
+	// The wait for people begins when the first person sits down, and begins again if everyone leaves.
+	var fill <-chan time.Time
+	var fillTimer *time.Timer
+	defer func() {
+		if fillTimer != nil {
+			fillTimer.Stop()
+		}
+	}()
+	ticker := time.NewTicker(timerTick)
+	defer ticker.Stop()
+	// The idle period begins when the table is created, and again whenever the last person leaves.
+	var idle <-chan time.Time
+	var idleTimer *time.Timer
+	defer func() {
+		if idleTimer != nil {
+			idleTimer.Stop()
+		}
+	}()
+	var robot <-chan time.Time
+	for !t.over() {
+		t.announceTurn()
+		if waiting := t.waiting(); waiting && fillTimer == nil && t.opts.RobotWait > 0 {
+			fillTimer = time.NewTimer(t.opts.RobotWait)
+			fill = fillTimer.C
+		} else if !waiting && fillTimer != nil {
+			fillTimer.Stop()
+			fillTimer, fill = nil, nil
+		}
+		if empty := t.empty(); empty && idleTimer == nil && t.lobby.cfg.Idle > 0 {
+			idleTimer = time.NewTimer(t.lobby.cfg.Idle)
+			idle = idleTimer.C
+		} else if !empty && idleTimer != nil {
+			idleTimer.Stop()
+			idleTimer, idle = nil, nil
+		}
+		if robot == nil && t.robotToPlay() {
+			robot = time.After(t.lobby.cfg.RobotDelay)
+		}
+		select {
+		case f := <-t.commands:
+			f()
+			t.start()
+		case <-fill:
+			t.fillWithRobots()
+			t.start()
+		case <-idle:
+			return
+		case <-robot:
+			robot = nil
+			if t.robotToPlay() {
+				// Robots fall back to their first card if their strategy fails, so they can always play.
+				t.mgr.PlayTurn(ctx)
+			}
+		case <-ticker.C:
+			t.releaseSeats()
+			if t.mgr.Playing() {
+				if err := t.mgr.CheckTurnTimer(); err != nil {
+					// A timeout that cannot be enforced would time out again on every tick, so the game ends instead.
+					t.mgr.EndGame()
+				}
+			}
+		case <-ctx.Done():
+			if t.mgr.Playing() {
+				t.mgr.EndGame()
+			}
+			return
+		}
+	}
+}
+
+// Seat is a person's place at a table in a Lobby, through which they play their game.
+// It is safe to use from any goroutine.
+type Seat struct {
+	table  *LobbyTable
+	player *NNPlayer
+	token  string
+	turn   chan struct{}
+	// The connection state is only changed on the table's goroutine.
+	lost       time.Time     // when the person lost their connection, or the zero time while they are connected
+	seen       int           // the sequence number of the last Event the person saw before they lost their connection
+	replaced   bool          // whether the seat has been given up, to a robot or to someone else
+	conn       int           // the generation of the latest connection to the seat
+	superseded chan struct{} // closed when someone reconnects to the seat, and replaced for the new connection
+	// The chat state is only changed on the table's goroutine.
+	muted   bool
+	limiter chatLimiter
+}
+
+// Table returns the table the seat is at.
+func (s *Seat) Table() *LobbyTable {
+	return s.table
+}
+
+// Name returns the name of the person in the seat.
+func (s *Seat) Name() string {
+	return s.player.Name()
+}
+
+// View returns what the person in the seat is allowed to know about the game.
+// It returns an error if the game has not started, or the table has closed.
+func (s *Seat) View() (v NNView, err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if !mgr.Playing() {
+			err = errors.New("the game has not started")
+			return
+		}
+		v = mgr.View(s.player.ID())
+	})
+	if doErr != nil {
+		return NNView{}, doErr
+	}
+	return v, err
+}
+
+// Turns returns a channel that receives whenever it becomes the person's turn.
+func (s *Seat) Turns() <-chan struct{} {
+	return s.turn
+}
+
+// Play plays the card at index i of the person's hand.
+// It returns an error if the card cannot be played, such as when it is not their turn.
+func (s *Seat) Play(i int) (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if !mgr.Playing() {
+			err = errors.New("the game has not started")
+			return
+		}
+		if s.replaced {
+			err = errors.New("you no longer have a seat at the table")
+			return
+		}
+		err = playCardAt(s.player, i)
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// Undo takes back the latest card the person played, if the table's rules allow take-backs
+// and the next player has not yet acted.
+func (s *Seat) Undo() (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if s.replaced {
+			err = errors.New("you no longer have a seat at the table")
+			return
+		}
+		err = mgr.Undo(s.player)
+	})
+	if doErr != nil {
+		return doErr
+	}
+	return err
+}
+
+// Leave gives up the seat before the game has started.
+// It returns an error once the game has started.
+func (s *Seat) Leave() (err error) {
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if !s.table.unseat(s) {
+			err = errors.New("the game has already started")
+		}
+	})
+	if doErr != nil {
+		return doErr
+	}
+	if err == nil {
+		s.table.lobby.endSession(s)
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package cards
+
+import (
+	"shuffle/utils"
+	"testing"
+	"time"
+)
+
+// testLobby is a lobby whose quick-match tables fill with robots almost at once, and whose robots never pause.
+var testLobby = LobbyConfig{Seats: 3, RobotWait: 20 * time.Millisecond}
+
+// waitClosed waits for a table to close.
+func waitClosed(t *testing.T, table *LobbyTable) {
+	select {
+	case <-table.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("table never closed")
+	}
+}
+
+// playOut plays the first card in the seat's hand whenever it is their turn, until the game is over.
+func playOut(t *testing.T, seat *Seat) {
+	for {
+		v, err := seat.View()
+		if err == errTableClosed {
+			return
+		} else if err == nil && v.Current == v.Seat && v.Active[v.Seat] {
+			seat.Play(0)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLobbyTables(t *testing.T) {
+	lobby := NewLobby(testLobby)
+	defer lobby.Close()
+
+	_, err := lobby.CreateTable(TableOptions{Seats: 1})
+	utils.Error(t, err != nil, true, "too few seats")
+	public, err := lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	private, err := lobby.CreateTable(TableOptions{Seats: 3, Private: true})
+	utils.Fatal(t, err, nil)
+	utils.Error(t, public.Code(), "")
+	utils.Error(t, len(private.Code()), joinCodeLength)
+
+	_, err = lobby.Join(public.ID(), "Alice")
+	utils.Error(t, err, nil)
+	_, err = lobby.Join(public.ID(), "Alice")
+	utils.Error(t, err != nil, true, "names are unique at a table")
+	_, err = lobby.Join(private.ID(), "Bob")
+	utils.Error(t, err != nil, true, "private tables need their join code")
+	_, err = lobby.Join(private.Code(), "Bob")
+	utils.Error(t, err, nil)
+	_, err = lobby.Join("nowhere", "Bob")
+	utils.Error(t, err != nil, true, "unknown table")
+
+	utils.Error(t, lobby.Tables(), []TableInfo{
+		{ID: public.ID(), Settings: *NNDefaultSettings, Seats: 2, Players: []string{"Alice"}, Host: "Alice"},
+	}, "private tables are not listed")
+	utils.Error(t, lobby.Tables()[0].Open(), true)
+
+	bob, err := lobby.Join(public.ID(), "Bob")
+	utils.Fatal(t, err, nil)
+	info := public.Info()
+	utils.Error(t, info.Playing, true, "the game begins once the table is full")
+	utils.Error(t, info.Open(), false)
+	_, err = lobby.Join(public.ID(), "Charlie")
+	utils.Error(t, err != nil, true, "full table")
+	utils.Error(t, bob.Leave() != nil, true, "leaving once the game has started")
+
+	names := public.Events().History()[0].Names
+	utils.Error(t, names, []string{"Alice", "Bob"})
+}
+
+func TestQuickMatch(t *testing.T) {
+	lobby := NewLobby(testLobby)
+	defer lobby.Close()
+
+	quick := *NNDefaultSettings
+	quick.LivesPerPlayer = 1
+	alice, err := lobby.QuickMatch("Alice", &quick)
+	utils.Fatal(t, err, nil)
+	bob, err := lobby.QuickMatch("Bob", &quick)
+	utils.Fatal(t, err, nil)
+	charlie, err := lobby.QuickMatch("Charlie", nil)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, bob.Table(), alice.Table(), "players who prefer the same rules are matched")
+	utils.Error(t, charlie.Table() != alice.Table(), true, "players who prefer other rules are not")
+
+	go playOut(t, alice)
+	go playOut(t, bob)
+	playOut(t, charlie)
+	waitClosed(t, alice.Table())
+	waitClosed(t, charlie.Table())
+
+	info := alice.Table().Info()
+	utils.Error(t, info.Players, []string{"Alice", "Bob"})
+	utils.Error(t, info.Robots, 1, "the empty seat is filled by a robot")
+	utils.Error(t, charlie.Table().Info().Robots, 2)
+	last := alice.Table().Events().History()
+	utils.Error(t, last[len(last)-1].Type, EventGameEnded)
+	utils.Error(t, len(lobby.Tables()), 0, "tables close once their game is over")
+}
+
+func TestLobbyLeave(t *testing.T) {
+	lobby := NewLobby(testLobby)
+	defer lobby.Close()
+
+	alice, err := lobby.QuickMatch("Alice", nil)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, alice.Leave(), nil)
+	time.Sleep(2 * testLobby.RobotWait)
+	utils.Error(t, alice.Table().Info().Robots, 0, "empty tables are not filled by robots")
+	bob, err := lobby.QuickMatch("Bob", nil)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, bob.Table(), alice.Table(), "the table is still open")
+	go playOut(t, bob)
+	waitClosed(t, bob.Table())
+	utils.Error(t, bob.Table().Info().Players, []string{"Bob"})
+}
+
+func TestLobbySettings(t *testing.T) {
+	cfg := testLobby
+	settings := *NNDefaultSettings
+	settings.TurnLimit, settings.OnTimeout = 30*time.Second, TimeoutForfeitLife
+	cfg.Settings = &settings
+	lobby := NewLobby(cfg)
+	defer lobby.Close()
+
+	alice, err := lobby.QuickMatch("Alice", nil)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, alice.Table().Info().Settings, settings, "quick-match tables follow the lobby's rules")
+	table, err := lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	utils.Error(t, table.Info().Settings, settings, "new tables follow the lobby's rules")
+}
+
+func TestLobbyIdle(t *testing.T) {
+	cfg := testLobby
+	cfg.Idle = 20 * time.Millisecond
+	lobby := NewLobby(cfg)
+	defer lobby.Close()
+
+	table, err := lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	alice, err := lobby.Join(table.ID(), "Alice")
+	utils.Fatal(t, err, nil)
+	time.Sleep(2 * cfg.Idle)
+	utils.Error(t, len(lobby.Tables()), 1, "tables with someone seated stay open")
+	utils.Fatal(t, alice.Leave(), nil)
+	waitClosed(t, table)
+	_, ok := lobby.Table(table.ID())
+	utils.Error(t, ok, false, "the abandoned table is gone")
+
+	table, err = lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	waitClosed(t, table)
+}
+
+func TestLobbyClose(t *testing.T) {
+	lobby := NewLobby(testLobby)
+	table, err := lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	lobby.Join(table.ID(), "Alice")
+	bob, err := lobby.Join(table.ID(), "Bob")
+	utils.Fatal(t, err, nil)
+	lobby.Close()
+	waitClosed(t, table)
+	utils.Error(t, bob.Play(0), errTableClosed)
+	_, err = lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Error(t, err != nil, true, "closed lobbies have no new tables")
+}
@@ -0,0 +1,270 @@
+package cards
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Languages lists the languages the games can be played in. English is the default.
+var Languages = []language.Tag{language.English, language.Italian, language.French}
+
+// translations maps each message shown to players, in English, to its Italian and French translations.
+// Messages are format strings: arguments may be reordered with explicit indexes such as %[2]d.
+var translations = []struct{ en, it, fr string }{
+	// 99
+	{"Welcome to 99!", "Benvenuti a 99!", "Bienvenue au 99 !"},
+	{"%v, it's your turn. Select a card from 1-%v", "%v, tocca a te. Scegli una carta da 1 a %v", "%v, c'est ton tour. Choisis une carte de 1 à %v"},
+	{"Or enter u to take back the last card played.", "Oppure inserisci u per riprendere l'ultima carta giocata.", "Ou entre u pour reprendre la dernière carte jouée."},
+	{"Please make a valid selection.", "Fai una scelta valida.", "Fais un choix valide."},
+	{"Count: %v", "Conteggio: %v", "Total : %v"},
+	{"  %d) %v -> count %d, next player forced to bust %.1f%%", "  %d) %v -> conteggio %d, il prossimo giocatore costretto a sballare %.1f%%", "  %d) %v -> total %d, joueur suivant forcé à sauter %.1f%%"},
+	{"  %d) %v -> count %d, busts!", "  %d) %v -> conteggio %d, sballa!", "  %d) %v -> total %d, saute !"},
+	{"%d points busts %d! %v loses!", "%d punti superano %d! %v perde!", "%d points dépassent %d ! %v perd !"},
+	{"%v takes back %v. The count is %d again.", "%v riprende %v. Il conteggio torna a %d.", "%v reprend %v. Le total revient à %d."},
+	{"Thanks for playing!", "Grazie per aver giocato!", "Merci d'avoir joué !"},
+	{"%v wins! Thanks for playing!", "%v vince! Grazie per aver giocato!", "%v gagne ! Merci d'avoir joué !"},
+	{"It's %v's turn. The count is %d and play goes %v. You hold %v.", "Tocca a %v. Il conteggio è %d e si gioca in %v. Hai in mano %v.", "C'est le tour de %v. Le total est %d et le jeu tourne dans le %v. Tu as %v."},
+	{"%v and %v", "%v e %v", "%v et %v"},
+	{"Watching %v play 99", "Stai guardando %v giocare a 99", "Tu regardes %v jouer au 99"},
+	{"The game is over", "La partita è finita", "La partie est terminée"},
+	{"Commentary, %v behind:", "Commento, con %v di ritardo:", "Commentaire, avec %v de retard :"},
+	{"Draw pile: %d cards", "Mazzo: %d carte", "Pioche : %d cartes"},
+	{"Discard pile: %d cards", "Scarti: %d carte", "Défausse : %d cartes"},
+
+	// The full-screen interface to 99
+	{"Someone", "Qualcuno", "Quelqu'un"},
+	{"New round: %v turned up, count %d", "Nuovo giro: esce %v, conteggio %d", "Nouvelle manche : %v retourné, total %d"},
+	{"%s plays %v, count %d", "%s gioca %v, conteggio %d", "%s joue %v, total %d"},
+	{"Play reverses direction", "Il giro cambia senso", "Le sens du jeu s'inverse"},
+	{"%s busts at %d!", "%s sballa a %d!", "%s saute à %d !"},
+	{"%s is out of the game", "%s è fuori dal gioco", "%s est éliminé"},
+	{"%s wins the game!", "%s vince la partita!", "%s gagne la partie !"},
+	{"%s takes back %v, count %d", "%s riprende %v, conteggio %d", "%s reprend %v, total %d"},
+	{"%s ran out of time", "%s ha esaurito il tempo", "%s n'a plus de temps"},
+	{"%s lost their connection", "%s ha perso la connessione", "%s a perdu la connexion"},
+	{"%s is back", "%s: connessione ripristinata", "%s est de retour"},
+	{"A robot plays for %s", "Un robot gioca al posto di %s", "Un robot joue à la place de %s"},
+	{"Game over. Press any key to leave.", "Partita finita. Premi un tasto per uscire.", "Partie terminée. Appuie sur une touche pour quitter."},
+	{"There is nothing to take back.", "Non c'è niente da riprendere.", "Il n'y a rien à reprendre."},
+	{"Round %d", "Giro %d", "Manche %d"},
+	{"%ds left", "%ds rimasti", "%ds restantes"},
+	{"%s's hand:", "Mano di %s:", "Main de %s :"},
+	{"←/→ choose  enter play  1-9 play  u take back  q quit", "←/→ scegli  invio gioca  1-9 gioca  u riprendi  q esci", "←/→ choisir  entrée jouer  1-9 jouer  u reprendre  q quitter"},
+	{"out", "fuori", "éliminé"},
+	{"clockwise", "senso orario", "sens horaire"},
+	{"counterclockwise", "senso antiorario", "sens antihoraire"},
+
+	// The 99 table protocol
+	{"Welcome to 99! What's your name?", "Benvenuti a 99! Come ti chiami?", "Bienvenue au 99 ! Comment t'appelles-tu ?"},
+	{"Names have 1-%d characters. What's your name?", "I nomi hanno da 1 a %d caratteri. Come ti chiami?", "Les noms ont de 1 à %d caractères. Comment t'appelles-tu ?"},
+	{"Hello, %s! Enter help for the list of commands.", "Ciao, %s! Inserisci help per l'elenco dei comandi.", "Bonjour, %s ! Entre help pour la liste des commandes."},
+	{"Goodbye!", "Arrivederci!", "Au revoir !"},
+	{"You are already at table %s.", "Sei già al tavolo %s.", "Tu es déjà à la table %s."},
+	{"You are not at a table. Enter tables, new, join or quick to find one.", "Non sei a un tavolo. Inserisci tables, new, join o quick per trovarne uno.", "Tu n'es à aucune table. Entre tables, new, join ou quick pour en trouver une."},
+	{"Unknown command %q. Enter help for the list of commands.", "Comando sconosciuto %q. Inserisci help per l'elenco dei comandi.", "Commande inconnue %q. Entre help pour la liste des commandes."},
+	{"In the lobby: tables, new [seats] [private], join <table or code>, quick, resume <token>", "Nella sala: tables, new [posti] [private], join <tavolo o codice>, quick, resume <token>", "Dans le salon : tables, new [places] [private], join <table ou code>, quick, resume <jeton>"},
+	{"At a table: hand, play <card>, undo, count, who, chat <message>, emote [name], rules, leave", "Al tavolo: hand, play <carta>, undo, count, who, chat <messaggio>, emote [nome], rules, leave", "À table : hand, play <carte>, undo, count, who, chat <message>, emote [nom], rules, leave"},
+	{"For the host: mute <name>, unmute <name>, kick <name> [robot], pause, unpause, robot add, robot remove, rules <rule> <value>", "Per l'host: mute <nome>, unmute <nome>, kick <nome> [robot], pause, unpause, robot add, robot remove, rules <regola> <valore>", "Pour l'hôte : mute <nom>, unmute <nom>, kick <nom> [robot], pause, unpause, robot add, robot remove, rules <règle> <valeur>"},
+	{"Emotes: %v", "Emote: %v", "Émotes : %v"},
+	{"The host has muted %s", "L'host ha silenziato %s", "L'hôte a rendu %s muet"},
+	{"%s may chat again", "%s può di nuovo scrivere in chat", "%s peut de nouveau discuter"},
+	{"The host has paused the game", "L'host ha messo in pausa la partita", "L'hôte a mis la partie en pause"},
+	{"The game resumes", "La partita riprende", "La partie reprend"},
+	{"The host has removed %s from the table", "L'host ha allontanato %s dal tavolo", "L'hôte a retiré %s de la table"},
+	{"A robot sits down at the table", "Un robot si siede al tavolo", "Un robot s'assoit à la table"},
+	{"%s sits down at the table", "%s si siede al tavolo", "%s s'assoit à la table"},
+	{"A robot leaves the table", "Un robot lascia il tavolo", "Un robot quitte la table"},
+	{"%s leaves the table", "%s lascia il tavolo", "%s quitte la table"},
+	{"The host has changed the rules", "L'host ha cambiato le regole", "L'hôte a changé les règles"},
+	{"The new rules apply from this round", "Le nuove regole valgono da questo giro", "Les nouvelles règles s'appliquent à partir de cette manche"},
+	{"Enter robot add or robot remove.", "Inserisci robot add o robot remove.", "Entre robot add ou robot remove."},
+	{"Done. The robots will change when the next round is dealt.", "Fatto. I robot cambieranno alla prossima mano.", "C'est fait. Les robots changeront à la prochaine donne."},
+	{"Enter rules and a rule with its value: lives 1-%d, cards 1-%d, max 20-%d or undo on/off.", "Inserisci rules e una regola con il suo valore: lives 1-%d, cards 1-%d, max 20-%d o undo on/off.", "Entre rules et une règle avec sa valeur : lives 1-%d, cards 1-%d, max 20-%d ou undo on/off."},
+	{"Rules: lives %d, cards %d, up to %d, %s", "Regole: vite %d, carte %d, fino a %d, %s", "Règles : vies %d, cartes %d, jusqu'à %d, %s"},
+	{"no take-backs", "senza ripensamenti", "sans reprise"},
+	{"take-backs allowed", "ripensamenti ammessi", "reprises permises"},
+	{"Watch out for those 9's!", "Attenti a quei 9!", "Attention aux 9 !"},
+	{"Good game!", "Bella partita!", "Bien joué !"},
+	{"Good luck, everyone!", "Buona fortuna a tutti!", "Bonne chance à tous !"},
+	{"That was close!", "C'è mancato poco!", "C'était moins une !"},
+	{"Oops!", "Ops!", "Oups !"},
+	{"Wow!", "Wow!", "Waouh !"},
+	{"Thanks!", "Grazie!", "Merci !"},
+	{"Who's gonna shuffle?", "Chi mescola?", "Qui va mélanger ?"},
+	{"At any time: help, quit", "In qualsiasi momento: help, quit", "À tout moment : help, quit"},
+	{"There are no tables. Enter new to set one up, or quick to play now.", "Non ci sono tavoli. Inserisci new per prepararne uno, o quick per giocare subito.", "Il n'y a aucune table. Entre new pour en créer une, ou quick pour jouer tout de suite."},
+	{"%s: %d/%d seats, %d lives, up to %d", "%s: %d/%d posti, %d vite, fino a %d", "%s : %d/%d places, %d vies, jusqu'à %d"},
+	{"(playing)", "(in gioco)", "(en jeu)"},
+	{"Your private table's join code is %s.", "Il codice del tuo tavolo privato è %s.", "Le code de ta table privée est %s."},
+	{"Error: %v", "Errore: %v", "Erreur : %v"},
+	{"You sit down at table %s. If you lose your connection, enter resume %s to return to your seat.", "Ti siedi al tavolo %s. Se perdi la connessione, inserisci resume %s per tornare al tuo posto.", "Tu t'assieds à la table %s. Si tu perds la connexion, entre resume %s pour retrouver ta place."},
+	{"The game begins: %v", "La partita comincia: %v", "La partie commence : %v"},
+	{"Your turn! The count is %d.", "Tocca a te! Il conteggio è %d.", "À toi ! Le total est %d."},
+	{"The table has closed. Enter tables, new, join or quick to play again.", "Il tavolo è chiuso. Inserisci tables, new, join o quick per giocare ancora.", "La table est fermée. Entre tables, new, join ou quick pour rejouer."},
+	{"Your hand: %v", "La tua mano: %v", "Ta main : %v"},
+	{"Enter play and the number of a card in your hand, e.g. play 2.", "Inserisci play e il numero di una carta della tua mano, ad es. play 2.", "Entre play et le numéro d'une carte de ta main, par ex. play 2."},
+	{"You leave the table.", "Lasci il tavolo.", "Tu quittes la table."},
+	{"Your seat has been resumed from another connection.", "Il tuo posto è stato ripreso da un'altra connessione.", "Ta place a été reprise depuis une autre connexion."},
+	{"Waiting for the game to begin: %v", "In attesa che la partita cominci: %v", "En attendant le début de la partie : %v"},
+	{"(you)", "(tu)", "(toi)"},
+
+	// Crazy Eights
+	{"Welcome to Crazy Eights!", "Benvenuti a Crazy Eights!", "Bienvenue au Huit américain !"},
+	{"Top: %v", "In cima: %v", "Dessus : %v"},
+	{"Top: %v (%v to follow)", "In cima: %v (si risponde a %v)", "Dessus : %v (%v à fournir)"},
+	{"Declare a suit: %v", "Dichiara un seme: %v", "Annonce une couleur : %v"},
+	{"%v declares %v!", "%v dichiara %v!", "%v annonce %v !"},
+	{"%v draws a card.", "%v pesca una carta.", "%v pioche une carte."},
+	{"%v cannot play and passes.", "%v non può giocare e passa.", "%v ne peut pas jouer et passe."},
+	{"%v wins!", "%v vince!", "%v gagne !"},
+	{"%v\t(p%v): \t%d points", "%v\t(p%v): \t%d punti", "%v\t(p%v): \t%d points"},
+
+	// Blackjack
+	{"Welcome to Blackjack!", "Benvenuti a Blackjack!", "Bienvenue au Blackjack !"},
+	{"%v, you have %d chips. Place a bet (0 to sit out)", "%v, hai %d fiches. Punta (0 per restare fuori)", "%v, tu as %d jetons. Mise (0 pour passer ton tour)"},
+	{"House shows %v", "Il banco mostra %v", "La banque montre %v"},
+	{"%v, you hold %v (%v). (h)it, (s)tand, (d)ouble or s(p)lit?", "%v, hai %v (%v). carta (h), stai (s), raddoppia (d) o dividi (p)?", "%v, tu as %v (%v). carte (h), rester (s), doubler (d) ou séparer (p) ?"},
+	{"soft %d", "%d morbido", "%d souple"},
+	{"hard %d", "%d duro", "%d dur"},
+	{"%v draws %v.", "%v pesca %v.", "%v tire %v."},
+	{"The house draws %v.", "Il banco pesca %v.", "La banque tire %v."},
+	{"%d busts! %v loses the hand.", "%d sballa! %v perde la mano.", "%d, ça saute ! %v perd la main."},
+	{"The house reveals %v (%v).", "Il banco scopre %v (%v).", "La banque révèle %v (%v)."},
+	{"%v wins %d chips!", "%v vince %d fiches!", "%v gagne %d jetons !"},
+	{"%v loses %d chips.", "%v perde %d fiches.", "%v perd %d jetons."},
+	{"%v pushes.", "%v pareggia.", "%v fait égalité."},
+	{"%v\t(p%v): \t%d chips", "%v\t(p%v): \t%d fiches", "%v\t(p%v): \t%d jetons"},
+
+	// Card names
+	{"Ace", "Asso", "As"},
+	{"Two", "Due", "Deux"},
+	{"Three", "Tre", "Trois"},
+	{"Four", "Quattro", "Quatre"},
+	{"Five", "Cinque", "Cinq"},
+	{"Six", "Sei", "Six"},
+	{"Seven", "Sette", "Sept"},
+	{"Eight", "Otto", "Huit"},
+	{"Nine", "Nove", "Neuf"},
+	{"Ten", "Dieci", "Dix"},
+	{"Jack", "Fante", "Valet"},
+	{"Queen", "Donna", "Dame"},
+	{"King", "Re", "Roi"},
+	{"%s of %s", "%s di %s", "%s de %s"},
+	{"a face-down card", "una carta coperta", "une carte face cachée"},
+	{"Clubs", "Fiori", "Trèfle"},
+	{"Diamonds", "Quadri", "Carreau"},
+	{"Hearts", "Cuori", "Cœur"},
+	{"Spades", "Picche", "Pique"},
+}
+
+// pluralTranslations maps messages that depend on a count to their singular and plural forms in each language,
+// in the order of Languages. The count is always the second argument.
+var pluralTranslations = map[string][][2]string{
+	"%s has %d lives left": {
+		{"%s has %d life left", "%s has %d lives left"},
+		{"A %s resta %d vita", "A %s restano %d vite"},
+		{"Il reste %[2]d vie à %[1]s", "Il reste %[2]d vies à %[1]s"},
+	},
+	"Table %s is waiting for %d more players.": {
+		{"Table %s is waiting for %d more player.", "Table %s is waiting for %d more players."},
+		{"Il tavolo %s aspetta ancora %d giocatore.", "Il tavolo %s aspetta altri %d giocatori."},
+		{"La table %s attend encore %d joueur.", "La table %s attend encore %d joueurs."},
+	},
+	"%s has %d seconds left": {
+		{"%s has %d second left", "%s has %d seconds left"},
+		{"A %s resta %d secondo", "A %s restano %d secondi"},
+		{"Il reste %[2]d seconde à %[1]s", "Il reste %[2]d secondes à %[1]s"},
+	},
+}
+
+// messages is the catalog of messages shown to players, in every language in Languages.
+var messages = newCatalog()
+
+// printer formats messages in the language chosen with SetLanguage.
+var printer = message.NewPrinter(language.English, message.Catalog(messages))
+
+// newCatalog builds the catalog of messages from their translations.
+func newCatalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for _, t := range translations {
+		b.SetString(language.Italian, t.en, t.it)
+		b.SetString(language.French, t.en, t.fr)
+	}
+	for key, forms := range pluralTranslations {
+		for i, tag := range Languages {
+			b.Set(tag, key, plural.Selectf(2, "%d", "one", forms[i][0], "other", forms[i][1]))
+		}
+	}
+	return b
+}
+
+// SetLanguage changes the language of the messages shown to players, and the way numbers are written in them.
+// Messages without a translation are shown in English.
+func SetLanguage(tag language.Tag) {
+	printer = message.NewPrinter(tag, message.Catalog(messages))
+}
+
+// MatchLanguage returns the supported language closest to a language tag such as "it" or "fr-CA",
+// or a POSIX locale such as "it_IT.UTF-8".
+// It returns false if none of the supported languages are close.
+func MatchLanguage(locale string) (language.Tag, bool) {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return language.English, false
+	}
+	_, i, confidence := language.NewMatcher(Languages).Match(tag)
+	if confidence == language.No {
+		return language.English, false
+	}
+	return Languages[i], true
+}
+
+// LanguageFromEnv returns the supported language of the user's locale, as set by the LC_ALL, LC_MESSAGES
+// or LANG environment variables. It returns English if the locale is unset or unsupported.
+func LanguageFromEnv() language.Tag {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			tag, _ := MatchLanguage(locale)
+			return tag
+		}
+	}
+	return language.English
+}
+
+// translate formats a message from the catalog in the language chosen with SetLanguage.
+func translate(key string, a ...interface{}) string {
+	return printer.Sprintf(key, a...)
+}
+
+// say prints a message from the catalog on a line of its own, in the language chosen with SetLanguage.
+func say(key string, a ...interface{}) {
+	fmt.Println(translate(key, a...))
+}
+
+// localString displays a Rank in long form, in the language chosen with SetLanguage.
+func (r Rank) localString() string {
+	return translate(r.longString())
+}
+
+// localString displays a Suit in long form, in the language chosen with SetLanguage.
+func (s Suit) localString() string {
+	return translate(s.longString())
+}
+
+// longString displays a Card in long form, such as "Queen of Spades", in the language chosen with SetLanguage.
+// The zero Card is a face-down card.
+func (c Card) longString() string {
+	if c == (Card{}) {
+		return translate("a face-down card")
+	}
+	return translate("%s of %s", c.rank.localString(), c.suit.localString())
+}
@@ -0,0 +1,100 @@
+package cards
+
+import (
+	"shuffle/render"
+	"shuffle/utils"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		locale string
+		tag    language.Tag
+		ok     bool
+	}{
+		{"en", language.English, true},
+		{"it", language.Italian, true},
+		{"fr-CA", language.French, true},
+		{"it_IT.UTF-8", language.Italian, true},
+		{"fr_FR@euro", language.French, true},
+		{"de", language.English, false},
+		{"C", language.English, false},
+		{"", language.English, false},
+	}
+	for _, test := range tests {
+		tag, ok := MatchLanguage(test.locale)
+		utils.Error(t, tag, test.tag, test.locale)
+		utils.Error(t, ok, test.ok, test.locale)
+	}
+}
+
+func TestLanguageFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	utils.Error(t, LanguageFromEnv(), language.French)
+	t.Setenv("LC_ALL", "it_IT.UTF-8")
+	utils.Error(t, LanguageFromEnv(), language.Italian, "LC_ALL overrides LANG")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANG", "")
+	utils.Error(t, LanguageFromEnv(), language.English, "unset")
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLanguage(language.English)
+	tests := []struct {
+		tag  language.Tag
+		key  string
+		args []interface{}
+		want string
+	}{
+		{language.English, "Welcome to 99!", nil, "Welcome to 99!"},
+		{language.Italian, "Welcome to 99!", nil, "Benvenuti a 99!"},
+		{language.French, "Welcome to 99!", nil, "Bienvenue au 99 !"},
+		{language.Italian, "%v, it's your turn. Select a card from 1-%v", []interface{}{"Alice", 3}, "Alice, tocca a te. Scegli una carta da 1 a 3"},
+		{language.French, "%d points busts %d! %v loses!", []interface{}{105, 99, "Bob"}, "105 points dépassent 99 ! Bob perd !"},
+		{language.English, "%s has %d lives left", []interface{}{"Alice", 1}, "Alice has 1 life left"},
+		{language.English, "%s has %d lives left", []interface{}{"Alice", 2}, "Alice has 2 lives left"},
+		{language.Italian, "%s has %d lives left", []interface{}{"Alice", 1}, "A Alice resta 1 vita"},
+		{language.French, "%s has %d lives left", []interface{}{"Alice", 2}, "Il reste 2 vies à Alice"},
+		{language.English, "%v, you have %d chips. Place a bet (0 to sit out)", []interface{}{"Dan", 1500}, "Dan, you have 1,500 chips. Place a bet (0 to sit out)"},
+		{language.Italian, "%v, you have %d chips. Place a bet (0 to sit out)", []interface{}{"Dan", 1500}, "Dan, hai 1.500 fiches. Punta (0 per restare fuori)"},
+		{language.Italian, "a message with no translation", nil, "a message with no translation"},
+	}
+	for _, test := range tests {
+		SetLanguage(test.tag)
+		utils.Error(t, translate(test.key, test.args...), test.want, test.tag.String())
+	}
+
+	SetLanguage(language.French)
+	utils.Error(t, Queen.localString(), "Dame")
+	utils.Error(t, Spades.localString(), "Pique")
+	utils.Error(t, Queen.longString(), "Queen", "long names used in code are never translated")
+	utils.Error(t, NewCard(Queen, Spades).longString(), "Dame de Pique")
+	SetLanguage(language.Italian)
+	utils.Error(t, NewCard(Ace, Hearts).longString(), "Asso di Cuori")
+	utils.Error(t, listCards(Hand{NewCard(Two, Clubs), NewCard(King, Diamonds)}), "Due di Fiori e Re di Quadri")
+}
+
+func TestNarration(t *testing.T) {
+	defer func(r render.Renderer) { cardRenderer = r }(cardRenderer)
+	SetCardStyle(render.Names)
+	SetCardPalette(render.NoColour)
+
+	mgr := new(NNGameManager)
+	players := []*NNPlayer{NewNNPlayer("Alice"), NewNNPlayer("Bob")}
+	mgr.StartGame(players, 0, NNDefaultSettings)
+	players[0].ReplaceHand(Hand{NewCard(Queen, Spades), NewCard(Two, Hearts), NewCard(Nine, Clubs)})
+	mgr.count = 42
+	utils.Error(t, mgr.describeTurn(players[0]), "It's Alice's turn. The count is 42 and play goes clockwise. "+
+		"You hold Queen of Spades, Two of Hearts and Nine of Clubs. Alice has 3 lives left.")
+
+	line, _ := describeNNEvent(tableNames(mgr.Table()), Event{Type: EventCardPlayed, Seat: 0, Cards: []Card{NewCard(Queen, Spades)}, Count: 42})
+	utils.Error(t, sentence(line), "Alice plays Queen of Spades, count 42.")
+	utils.Error(t, sentence("Alice busts at 100!"), "Alice busts at 100!")
+	utils.Error(t, listCards(Hand{NewCard(Ace, Spades)}), "Ace of Spades")
+	utils.Error(t, NewCard(Ace, Spades).colourString(), "Ace of Spades", "cards in sentences are named")
+	utils.Error(t, Card{}.longString(), "a face-down card")
+}
@@ -1,9 +1,12 @@
 package cards
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"shuffle/utils"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -31,6 +34,10 @@ type NNGameSettings struct {
 	LivesPerPlayer int
 	MaxCount       int
 	WildCards      NNWildCards
+	Undo           bool            // whether players may take back their last card before the next player acts
+	TurnLimit      time.Duration   // the most time a player may take over their turn, or 0 for no limit
+	TurnWarning    time.Duration   // how long before the limit players are warned they are running out of time
+	OnTimeout      NNTimeoutAction // what happens to players who run out of time
 	// TODO: add AutoPickup as a setting (eventually needed for AI players)
 }
 
@@ -47,128 +54,289 @@ var NNDefaultSettings = &NNGameSettings{
 	},
 }
 
-// GameManager is an interface for entities that specify and enforce the rules of a specific game.
-// It contains the logic for starting and ending games, maintains the order of play,
-// validates player moves, and shifts cards between players and dealers.
-type GameManager interface {
-	NewGame()
-	StartGame(humans []*NNPlayer, robots int, settings *NNGameSettings)
-	Deal()
-	Play(p *Player, h Hand)
-	EndGame()
+// Game returns the name of the game these Rules are for.
+func (set *NNGameSettings) Game() string {
+	return "99"
 }
 
-// NNGameManager is an implementation of IGameManager that plays 99.
+// HandSize returns the number of cards dealt to each Player.
+func (set *NNGameSettings) HandSize() int {
+	return set.CardsPerPlayer
+}
+
+// Decks returns the number of decks to use in the Shoe for the given number of Players.
+func (set *NNGameSettings) Decks(numPlayers int) int {
+	return MinDecks(set.CardsPerPlayer, set.WildCards, numPlayers)
+}
+
+// ScoreRank determines the effect of the rank on the given count under these settings.
+// Assumes count-altering wild cards (Zero, NinetyNine, MinusTen), are all different ranks,
+// as one Rank card cannot have multiple competing effects on the Count.
+// Assumes Reverse has no impact on the count, unless it also happens to be a wild card.
+func (set *NNGameSettings) ScoreRank(count int, r Rank) (toAdd int, err error) {
+	wilds := set.WildCards
+	switch r {
+	case wilds.NinetyNine:
+		toAdd = set.MaxCount - count
+	case wilds.MinusTen:
+		toAdd = -10
+	case wilds.Zero, wilds.Reverse:
+		// no change to the count
+	case Ace:
+		toAdd = 1
+	case Jack, Queen, King:
+		toAdd = 10
+	default:
+		toAdd, err = strconv.Atoi(string(r))
+	}
+	return toAdd, err
+}
+
+// IsWild returns true if the rank is one of the wild cards.
+func (set *NNGameSettings) IsWild(r Rank) bool {
+	w := set.WildCards
+	return r == w.Reverse || r == w.NinetyNine || r == w.MinusTen || r == w.Zero
+}
+
+// NNGameManager is an implementation of GameManager that plays 99.
 // It maintains the overall count and the rules for scoring cards that are played.
 // It keeps track of the order of play and ensures that players only play valid cards, during their turn.
+// A game is played over several rounds: each time a player busts they lose a life,
+// and the last player with lives remaining wins.
 type NNGameManager struct {
 	settings   *NNGameSettings
-	players    map[int]NNPlayerStats
+	players    map[int]*NNPlayerStats
+	table      *Table
 	dealer     *dealer
+	events     *EventStream
+	rng        Randomizer
+	hints      bool
+	fullScreen bool
+	narrated   bool // whether the command line describes the game in plain sentences, for screen readers
+	profiles   *ProfileStore
 	playing    bool
 	round      int
 	count      int
-	currPlayer int
-	direction  int
+	leader     int
+	undo       *nnUndo // the state of the game before the latest card was played, if it can be taken back
+	clock      Clock
+	turnStart  time.Time
+	turn       int             // the number of turns begun, so that each turn can be told apart
+	warned     bool            // whether the current player has been warned they are running out of time
+	paused     bool            // whether play is stopped until the game is resumed
+	pausedAt   time.Time       // when the game was paused
+	reseat     int             // the number of robots to seat, or if negative to unseat, when the next round is dealt
+	rules      *NNGameSettings // the rules to play by from when the next round is dealt, if they are changing
 	// TODO: Add a concurrency-safe field to keep track of which player can draw from the deck. Needed for AutoPickup.
 }
 
 // NewGame begins a game of 99 with a number of players. The game follows the default house rules.
 func (mgr *NNGameManager) NewGame(players []*NNPlayer) {
-	mgr.newGame(players, NNDefaultSettings)
+	mgr.newGame(players, 0, NNDefaultSettings)
+}
+
+// NewGameWithRobots begins a game of 99 with a number of human players and robot players.
+// The game follows the default house rules.
+func (mgr *NNGameManager) NewGameWithRobots(players []*NNPlayer, robots int) {
+	mgr.newGame(players, robots, NNDefaultSettings)
+}
+
+// NewGameWithSettings begins a game of 99 with a number of human players and robot players,
+// following custom rules.
+func (mgr *NNGameManager) NewGameWithSettings(players []*NNPlayer, robots int, settings *NNGameSettings) {
+	mgr.newGame(players, robots, settings)
 }
 
 // TODO: refactor as a server that can connect to multiple clients.
 
 // NewGame begins a game of 99 with a number of players and custom rules.
-// It currently only supports the command line version of 99.
-func (mgr *NNGameManager) newGame(players []*NNPlayer, settings *NNGameSettings) {
-	fmt.Println("Welcome to 99!")
-	mgr.StartGame(players, 0, settings)
+// It is played on a full-screen interface if it is enabled and the terminal supports it,
+// and on the plain command line otherwise.
+func (mgr *NNGameManager) newGame(players []*NNPlayer, robots int, settings *NNGameSettings) {
+	if mgr.events == nil {
+		mgr.events = NewEventStream()
+	}
+	if mgr.profiles != nil {
+		mgr.profiles.Track(mgr)
+	}
+	if mgr.fullScreen && !mgr.narrated {
+		if term, err := openTerminal(os.Stdin, os.Stdout); err == nil {
+			screen := newNNScreen(mgr)
+			mgr.events.Subscribe(screen.record)
+			mgr.StartGame(players, robots, settings)
+			screen.run(term)
+			term.Close()
+			if winner, ok := mgr.Winner(); ok {
+				say("%v wins! Thanks for playing!", winner.Name())
+			} else {
+				say("Thanks for playing!")
+			}
+			return
+		}
+	}
+	if mgr.narrated {
+		mgr.events.Subscribe(mgr.narrate)
+	} else {
+		mgr.events.Subscribe(mgr.announce)
+	}
+	mgr.StartGame(players, robots, settings)
 
 	for mgr.playing {
-		mgr.revealTable() // TODO: temporary, remove after debugging
 		player := mgr.CurrPlayer()
-		fmt.Printf("%v, it's your turn. Select a card from 1-%v\n", player.Name, len(player.hand))
-		var card int
-		for {
-			fmt.Printf("Count: %v\n", mgr.count)
-			_, err := fmt.Scanf("%d", &card)
-			if err != nil || card <= 0 || card > len(player.hand) {
-				fmt.Println("Please make a valid selection.")
-			} else {
-				// TODO: re-route control flow in a more elegant way
-				break
+		if nn, ok := player.(*NNPlayer); ok && nn.Robot() {
+			if err := mgr.PlayTurn(context.Background()); err != nil {
+				handlePlayError(err)
 			}
+			continue
 		}
-		err := player.playCardAt(card - 1)
+		if mgr.narrated {
+			fmt.Println(mgr.describeTurn(player))
+		} else {
+			mgr.revealTable() // TODO: temporary, remove after debugging
+		}
+		if mgr.hints {
+			showHints(Analyze(mgr.View(player.ID())))
+		}
+		last := mgr.lastHumanPlay()
+		card := promptCard(player, func() { say("Count: %v", mgr.count) }, last != nil)
+		if card == 0 {
+			mgr.EndGame()
+			break
+		} else if card == undoSelection {
+			if err := mgr.Undo(last); err != nil {
+				handlePlayError(err)
+			}
+			continue
+		}
+		err := playCardAt(player, card-1)
 		if err != nil {
 			handlePlayError(err)
 		}
 	}
-	// TODO: add multiple rounds and take advantage of players' lives
-	// TODO: announce results of each round once concluded
 }
 
 // StartGame initializes and begins a new game of 99.
 // It may create games that have both human and AI players.
+// Robot players are seated after the humans and play cautiously.
 func (mgr *NNGameManager) StartGame(humans []*NNPlayer, robots int, settings *NNGameSettings) {
+	players := append([]*NNPlayer{}, humans...)
+	for i := 0; i < robots; i++ {
+		players = append(players, NewNNRobot(fmt.Sprintf("Robot %d", i+1), NewCautiousStrategy()))
+	}
+
 	mgr.setSettings(settings)
-	mgr.players = make(map[int]NNPlayerStats)
-	for i, human := range humans {
-		human.id = i
-		human.mgr = mgr
-		mgr.players[i] = NNPlayerStats{
-			player: human,
+	if mgr.events == nil {
+		mgr.events = NewEventStream()
+	}
+	mgr.table = NewTable()
+	mgr.players = make(map[int]*NNPlayerStats)
+	for _, p := range players {
+		id := mgr.table.Sit(p, mgr)
+		mgr.players[id] = &NNPlayerStats{
+			player: p,
 			lives:  mgr.settings.LivesPerPlayer,
 		}
 	}
-	// TODO: support AI (robot) players
-	mgr.Deal()
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventGameCreated, Seat: NoSeat, Names: tableNames(mgr.table)})
 	mgr.round = 0
-	mgr.currPlayer = 0
-	mgr.direction = 1
+	mgr.leader = 0
 	mgr.playing = true
+	mgr.paused = false
+	mgr.reseat = 0
+	mgr.rules = nil
+	mgr.Deal()
 }
 
 // SetSettings installs custom rules if provided, else defaults to house rules.
+// Players who run out of time have the safest card played for them, unless the rules say otherwise.
 func (mgr *NNGameManager) setSettings(settings *NNGameSettings) {
-	if settings != nil {
-		mgr.settings = settings
-	} else {
-		mgr.settings = NNDefaultSettings
+	if settings == nil {
+		settings = NNDefaultSettings
+	}
+	if settings.TurnLimit > 0 && !settings.OnTimeout.Valid() {
+		defaulted := *settings
+		defaulted.OnTimeout = TimeoutSafestCard
+		settings = &defaulted
 	}
+	mgr.settings = settings
 }
 
-// Deal initializes the Dealer with an appropriately-sized shoe and deals cards to each player.
-// It also deals one card face up to begin the game.
-func (mgr *NNGameManager) Deal() {
-	set := mgr.settings
-	decks := MinDecks(set.CardsPerPlayer, set.WildCards, len(mgr.players))
-	mgr.dealer = NewDealer(decks, NewRng(), true)
+// SetProfiles records the outcome of command line games in the profiles of the players who have one.
+func (mgr *NNGameManager) SetProfiles(s *ProfileStore) {
+	mgr.profiles = s
+}
+
+// SetFullScreen turns on or off the full-screen interface for command line games.
+// Games fall back to the plain command line when the terminal does not support it,
+// such as when input or output is redirected.
+func (mgr *NNGameManager) SetFullScreen(on bool) {
+	mgr.fullScreen = on
+}
+
+// SetNarration turns on or off plain-sentence descriptions of the game on the command line,
+// which screen readers can read aloud. Players are told the state of the game at the start of each turn,
+// instead of being shown every card on the table, and the full-screen interface is not used.
+func (mgr *NNGameManager) SetNarration(on bool) {
+	mgr.narrated = on
+}
+
+// SetHints turns on or off the beginner hints shown to human players on the command line.
+// The full-screen interface shows the hint for the card the player has selected.
+func (mgr *NNGameManager) SetHints(on bool) {
+	mgr.hints = on
+}
 
-	for _, stat := range mgr.players {
-		hand := mgr.dealer.DealHand(set.CardsPerPlayer)
-		stat.player.ReplaceHand(hand)
+// SetRandomizer installs the Randomizer used to shuffle the Shoe each round.
+// By default, every round is shuffled by a securely seeded Randomizer.
+func (mgr *NNGameManager) SetRandomizer(rng Randomizer) {
+	mgr.rng = rng
+}
+
+// Deal begins a new round. It initializes the Dealer with an appropriately-sized shoe
+// and deals cards to each player still in the game.
+// It also deals one card face up to begin the round.
+func (mgr *NNGameManager) Deal() {
+	rng := mgr.rng
+	if rng == nil {
+		rng = NewRng()
 	}
+	mgr.dealer = dealTable(mgr.settings, mgr.table, rng)
+	mgr.table.ResetDirection()
+	mgr.table.SetCurrSeat(mgr.leader)
+	mgr.beginTurn()
+	mgr.round++
+	mgr.count = 0
+	mgr.undo = nil
+
 	h := mgr.dealer.DealHand(1)
 	initCount, _ := mgr.ScoreCard(h[0])
 	mgr.count = initCount
 	mgr.dealer.HandleDiscard(h)
+	mgr.emit(EventDealt, NoSeat, h)
 }
 
 // MinDecks calculates the minimum number of decks to use in the Shoe, to avoid endless games of 99.
 // It uses cards per player, designated wild cards, and the number of players to recommend a shoe size.
+// The Shoe holds at least twice as many cards as are dealt, so that the draw pile never runs dry
+// while most of the cards are in players' hands.
 func MinDecks(cardsEach int, wilds NNWildCards, numPlayers int) int {
-	// TODO: implement
-	return 1
+	dealt := numPlayers*cardsEach + 1
+	return utils.Max((2*dealt+CardsPerDeck-1)/CardsPerDeck, 1)
 }
 
 // Play validates a player's move, scores their card, and advances play to the next player.
-func (mgr *NNGameManager) Play(p *NNPlayer, h Hand) (err error) {
-	if mgr.CurrPlayer().id != p.id {
+func (mgr *NNGameManager) Play(p Player, h Hand) (err error) {
+	if !mgr.playing {
+		return errors.New("no game in progress")
+	} else if mgr.paused {
+		return errors.New("the game is paused")
+	} else if mgr.table.CurrSeat() != p.ID() {
 		return errors.New("playing out of turn")
-	} else if c, ok := mgr.getCardFromPlayer(p, h[0]); !ok {
+	} else if len(h) != 1 {
+		return errors.New("play exactly one card")
+	}
+	undo := mgr.snapshot(p, h[0])
+	if c, ok := mgr.getCardFromPlayer(p, h[0]); !ok {
 		return errors.New("cheating")
 	} else {
 		if toAdd, err := mgr.ScoreCard(c); err != nil {
@@ -176,116 +344,319 @@ func (mgr *NNGameManager) Play(p *NNPlayer, h Hand) (err error) {
 		} else {
 			mgr.count += toAdd
 			mgr.dealer.HandleDiscard(h)
+			mgr.emit(EventCardPlayed, p.ID(), h)
 		}
 		if mgr.count > mgr.settings.MaxCount {
 			mgr.DeclareLoser(p)
-			// TODO: re-route control flow in a more elegant way
-			return
+			return nil
 		}
 		mgr.reverseIfNeeded(c)
 		hand := mgr.dealer.DealHand(1)
 		p.AcceptCards(hand)
+		mgr.emit(EventDrew, p.ID(), nil)
 		mgr.AdvanceCurrPlayer()
+		mgr.beginTurn()
+		mgr.undo = undo
 	}
 	return err
 }
 
+// nnUndo is the state of a game of 99 just before a card was played, from which the play can be taken back.
+type nnUndo struct {
+	seat      int
+	card      Card
+	count     int
+	direction int
+	hand      Hand
+	dealer    *dealer
+	turnStart time.Time // when the player's turn began, so that taking a card back does not restart their turn timer
+	warned    bool
+}
+
+// snapshot records the state of the game before the player plays the card, if take-backs are allowed.
+func (mgr *NNGameManager) snapshot(p Player, c Card) *nnUndo {
+	if !mgr.settings.Undo {
+		return nil
+	}
+	return &nnUndo{
+		seat:      p.ID(),
+		card:      c,
+		count:     mgr.count,
+		direction: mgr.table.Direction(),
+		hand:      p.Hand(),
+		dealer:    mgr.dealer.copy(),
+		turnStart: mgr.turnStart,
+		warned:    mgr.warned,
+	}
+}
+
+// lastHumanPlay returns the human player who played the latest card, if they may still take it back.
+// On the command line, everyone shares the same terminal, so the next player is prompted on their behalf.
+func (mgr *NNGameManager) lastHumanPlay() Player {
+	if mgr.undo == nil {
+		return nil
+	}
+	if p, ok := mgr.table.Player(mgr.undo.seat); ok && mgr.CanUndo(p) {
+		if nn, ok := p.(*NNPlayer); !ok || !nn.Robot() {
+			return p
+		}
+	}
+	return nil
+}
+
+// CanUndo returns true if the player may take back the latest card played.
+func (mgr *NNGameManager) CanUndo(p Player) bool {
+	return mgr.playing && mgr.undo != nil && mgr.undo.seat == p.ID()
+}
+
+// Undo takes back the latest card played, if the player played it and the next player has not yet acted.
+// The count, direction of play, the player's hand, the card they picked up and the discard pile
+// are restored exactly as they were, and the turn returns to the player with the time they had left.
+// Cards that bust the count cannot be taken back. Take-backs must be allowed by the game's settings.
+func (mgr *NNGameManager) Undo(p Player) error {
+	if !mgr.settings.Undo {
+		return errors.New("take-backs are not allowed")
+	} else if !mgr.playing || mgr.undo == nil {
+		return errors.New("nothing to take back")
+	} else if mgr.paused {
+		return errors.New("the game is paused")
+	} else if mgr.undo.seat != p.ID() {
+		return errors.New("only the player who played the latest card can take it back")
+	}
+	u := mgr.undo
+	mgr.undo = nil
+	mgr.count = u.count
+	if mgr.table.Direction() != u.direction {
+		mgr.table.Reverse()
+	}
+	p.ReplaceHand(u.hand)
+	mgr.dealer = u.dealer
+	mgr.table.SetCurrSeat(u.seat)
+	mgr.beginTurn()
+	mgr.turnStart, mgr.warned = u.turnStart, u.warned
+	mgr.emit(EventUndone, u.seat, Hand{u.card})
+	return nil
+}
+
+// PlayTurn asks the current player's strategy to choose a card, and plays it.
+// If the strategy fails to choose a playable card, the first card in the player's hand is played.
+// It returns an error if the current player is not a robot.
+func (mgr *NNGameManager) PlayTurn(ctx context.Context) error {
+	p, ok := mgr.CurrPlayer().(*NNPlayer)
+	if !ok || !p.Robot() {
+		return errors.New("the current player is not a robot")
+	}
+	i, err := p.strategy.Choose(ctx, mgr.View(p.ID()))
+	if err != nil || i < 0 || i >= len(p.hand) {
+		i = 0
+	}
+	return playCardAt(p, i)
+}
+
 // GetCardFromPlayer removes a Card from a player's hand, if it exists.
 // It returns true and the Card if it exists, else false and an empty Card.
-func (mgr *NNGameManager) getCardFromPlayer(p *NNPlayer, c Card) (Card, bool) {
-	if v, ok := mgr.players[p.id]; ok {
-		for i, card := range v.player.hand {
-			if card == c {
-				v.player.hand = append(v.player.hand[:i], v.player.hand[i+1:]...)
-				return c, true
-			}
-		}
+func (mgr *NNGameManager) getCardFromPlayer(p Player, c Card) (Card, bool) {
+	if seated, ok := mgr.table.Player(p.ID()); ok && seated.removeCard(c) {
+		return c, true
 	}
 	return Card{}, false
 }
 
-// DeclareLoser announces the loser of the round and ends the game.
-func (mgr *NNGameManager) DeclareLoser(p *NNPlayer) {
-	// TODO: remove the *NNPlayer arg and just use currPlayer
-	fmt.Printf("%d points busts %d! %v loses!\n", mgr.count, mgr.settings.MaxCount, p.Name)
-	// TODO: decrement lives instead of just ending the game
+// DeclareLoser announces the loser of the round and takes one of their lives.
+// Players with no lives left are eliminated. If only one player remains, they win the game;
+// otherwise a new round is dealt, led by the next player after the loser.
+func (mgr *NNGameManager) DeclareLoser(p Player) {
+	mgr.emit(EventBusted, p.ID(), nil)
+	mgr.loseLife(p)
+}
+
+// loseLife takes one of the player's lives, eliminating them if they have none left.
+// If only one player remains, they win the game; otherwise a new round is dealt,
+// led by the next player after the one who lost the life. Any robots asked for are seated or unseated before it is dealt.
+func (mgr *NNGameManager) loseLife(p Player) {
+	id := p.ID()
+	stats := mgr.players[id]
+	stats.lives--
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventLifeLost, Seat: id, Count: mgr.count, Lives: stats.lives})
+	if stats.lives <= 0 {
+		mgr.eliminate(id)
+	}
+
+	if mgr.decided() {
+		return
+	}
+	mgr.applyRules()
+	mgr.seatRobots()
+	mgr.table.ResetDirection()
+	mgr.table.SetCurrSeat(id)
+	mgr.table.Advance()
+	mgr.leader = mgr.table.CurrSeat()
+	mgr.Deal()
+}
+
+// eliminate takes the player in the given seat out of the game.
+func (mgr *NNGameManager) eliminate(id int) {
+	mgr.table.Eliminate(id)
+	mgr.players[id].player.ReplaceHand(Hand{})
+	mgr.emit(EventEliminated, id, nil)
+}
+
+// decided ends the game once only one player remains, who wins it.
+// It returns true if the game is over.
+func (mgr *NNGameManager) decided() bool {
+	remaining := mgr.table.Remaining()
+	if len(remaining) > 1 {
+		return false
+	}
+	if len(remaining) == 1 {
+		mgr.emit(EventWon, remaining[0].ID(), nil)
+	}
 	mgr.EndGame()
+	return true
 }
 
 // ScoreCard determines the effect of the card on the count.
 func (mgr NNGameManager) ScoreCard(c Card) (toAdd int, err error) {
-	toAdd, err = mgr.ScoreRank(c.rank)
-	mgr.reverseIfNeeded(c)
-	return
+	return mgr.ScoreRank(c.rank)
 }
 
 // ScoreRank determines the effect of the rank on the count.
-// Assumes count-altering wild cards (Zero, NinetyNine, MinusTen), are all different ranks,
-// as one Rank card cannot have multiple competing effects on the Count.
-// Assumes Reverse has no impact on the count, unless it also happens to be a wild card.
 func (mgr *NNGameManager) ScoreRank(r Rank) (toAdd int, err error) {
-	set := mgr.settings
-	wilds := set.WildCards
-	switch r {
-	case wilds.NinetyNine:
-		toAdd = set.MaxCount - mgr.count
-	case wilds.MinusTen:
-		toAdd = -10
-	case wilds.Zero, wilds.Reverse:
-		// no change to the count
-	case Ace:
-		toAdd = 1
-	case Jack, Queen, King:
-		toAdd = 10
-	default:
-		toAdd, err = strconv.Atoi(string(r))
-	}
-	return toAdd, err
+	return mgr.settings.ScoreRank(mgr.count, r)
 }
 
 // ReverseIfNeeded reverses the direction of play if a Reverse card is played.
 func (mgr *NNGameManager) reverseIfNeeded(c Card) {
 	if c.rank == mgr.settings.WildCards.Reverse {
-		mgr.direction *= -1
+		mgr.table.Reverse()
+		mgr.emit(EventReversed, mgr.table.CurrSeat(), nil)
 	}
 }
 
 // EndGame ends the game.
 func (mgr *NNGameManager) EndGame() {
-	// TODO: end game only if there's one winner standing, declare winner
 	mgr.playing = false
-	fmt.Println("Thanks for playing!")
-	mgr.revealTable()
+	mgr.emit(EventGameEnded, NoSeat, nil)
 }
 
 // AdvanceCurrPlayer advances play to the next player in the circle, according to the direction of play.
 func (mgr *NNGameManager) AdvanceCurrPlayer() {
-	if curr, err := utils.Mod(mgr.currPlayer+mgr.direction, len(mgr.players)); err == nil {
-		mgr.currPlayer = curr
-	} else {
-		mgr.currPlayer = 0
-	}
+	mgr.table.Advance()
 }
 
 // CurrPlayer returns the player who is currently taking their turn.
-func (mgr *NNGameManager) CurrPlayer() *NNPlayer {
-	return mgr.players[mgr.currPlayer].player
+func (mgr *NNGameManager) CurrPlayer() Player {
+	return mgr.table.CurrPlayer()
 }
 
-// HandlePlayError handles invalid plays attempted by Players during gameplay.
-func handlePlayError(e error) {
-	// TODO: implement robust error handling
-	fmt.Println(errors.Cause(e))
+// Playing returns true while a game is in progress.
+func (mgr *NNGameManager) Playing() bool {
+	return mgr.playing
+}
+
+// Table returns the Table at which the game is being played.
+func (mgr *NNGameManager) Table() *Table {
+	return mgr.table
+}
+
+// Events returns the stream of Events emitted by the game.
+func (mgr *NNGameManager) Events() *EventStream {
+	return mgr.events
+}
+
+// SetEvents records the game's Events in the given stream rather than a new one,
+// so the game can be observed before it begins.
+func (mgr *NNGameManager) SetEvents(s *EventStream) {
+	mgr.events = s
+}
+
+// Count returns the running count.
+func (mgr *NNGameManager) Count() int {
+	return mgr.count
 }
 
-// Debugging Helper Methods
+// Round returns the number of the round being played, counting from 1.
+func (mgr *NNGameManager) Round() int {
+	return mgr.round
+}
 
-// RevealTable shows all cards in the game, including the draw pile, discard pile,
-// and every player's hand.
-func (mgr NNGameManager) revealTable() {
-	fmt.Printf("Count: %v\n", mgr.count)
-	for _, stat := range mgr.players {
-		stat.player.revealHand()
+// Lives returns the number of lives the player in the given seat has left.
+func (mgr *NNGameManager) Lives(seat int) int {
+	if stats, ok := mgr.players[seat]; ok {
+		return stats.lives
 	}
-	mgr.dealer.revealDecks()
+	return 0
+}
+
+// Winner returns the last player standing.
+// It returns false if the game has no winner yet.
+func (mgr *NNGameManager) Winner() (Player, bool) {
+	if remaining := mgr.table.Remaining(); !mgr.playing && len(remaining) == 1 {
+		return remaining[0], true
+	}
+	return nil, false
+}
+
+// NNView is everything a single player is allowed to know about a game of 99:
+// their own hand, and the public state of the table.
+type NNView struct {
+	Seat      int
+	Hand      Hand
+	Count     int
+	Direction int
+	Round     int
+	Current   int    // the seat of the player whose turn it is
+	Lives     []int  // the lives left for each seat
+	HandSizes []int  // the number of cards held by each seat
+	Active    []bool // whether each seat is still in the game
+	Discard   Shoe   // the cards played since the Shoe was last shuffled, oldest first
+	Settings  NNGameSettings
+}
+
+// View returns what the player in the given seat is allowed to know about the game.
+// The view is a copy, so changing it has no effect on the game.
+func (mgr *NNGameManager) View(seat int) NNView {
+	v := NNView{
+		Seat:      seat,
+		Count:     mgr.count,
+		Direction: mgr.table.Direction(),
+		Round:     mgr.round,
+		Current:   mgr.table.CurrSeat(),
+		Settings:  *mgr.settings,
+	}
+	if p, ok := mgr.table.Player(seat); ok {
+		v.Hand = p.Hand()
+	}
+	for _, p := range mgr.table.Players() {
+		v.Lives = append(v.Lives, mgr.Lives(p.ID()))
+		v.HandSizes = append(v.HandSizes, len(p.Hand()))
+		v.Active = append(v.Active, mgr.table.Active(p.ID()))
+	}
+	if mgr.dealer != nil {
+		v.Discard = append(Shoe{}, mgr.dealer.discard...)
+	}
+	return v
+}
+
+// Outcome returns the count that would result from playing the card.
+func (v NNView) Outcome(c Card) (int, error) {
+	toAdd, err := v.Settings.ScoreRank(v.Count, c.rank)
+	return v.Count + toAdd, err
+}
+
+// Safe returns true if playing the card would not bust the count.
+func (v NNView) Safe(c Card) bool {
+	count, err := v.Outcome(c)
+	return err == nil && count <= v.Settings.MaxCount
+}
+
+// emit records an Event at the current count.
+func (mgr *NNGameManager) emit(t EventType, seat int, cards Hand) {
+	mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: t, Seat: seat, Cards: cards, Count: mgr.count})
+}
+
+// HandlePlayError handles invalid plays attempted by Players during gameplay.
+func handlePlayError(e error) {
+	// TODO: implement robust error handling
+	fmt.Println(errors.Cause(e))
 }
@@ -0,0 +1,191 @@
+package cards
+
+import (
+	"context"
+	"shuffle/utils"
+	"testing"
+)
+
+// newTestGame starts a quiet game of 99 between the named players, using the house rules.
+func newTestGame(names ...string) (*NNGameManager, []*NNPlayer) {
+	mgr := new(NNGameManager)
+	players := make([]*NNPlayer, len(names))
+	for i, name := range names {
+		players[i] = NewNNPlayer(name)
+	}
+	mgr.StartGame(players, 0, nil)
+	return mgr, players
+}
+
+func TestPlay(t *testing.T) {
+	mgr, players := newTestGame("Alice", "Bob", "Charlie")
+	alice, bob := players[0], players[1]
+	alice.ReplaceHand(Hand{NewCard(Five, Hearts), NewCard(Four, Clubs), NewCard(Nine, Spades)})
+	bob.ReplaceHand(Hand{NewCard(Jack, Hearts), NewCard(Ten, Clubs), NewCard(Nine, Hearts)})
+	mgr.count = 50
+
+	err := bob.Play(Hand{NewCard(Jack, Hearts)})
+	utils.Error(t, err != nil, true, "error playing out of turn")
+
+	err = alice.Play(Hand{NewCard(Jack, Hearts)})
+	utils.Error(t, err != nil, true, "error playing a card not in hand")
+
+	utils.Fatal(t, alice.Play(Hand{NewCard(Five, Hearts)}), nil)
+	utils.Error(t, mgr.Count(), 55, "count")
+	utils.Error(t, len(alice.Hand()), 3, "cards after pickup")
+	utils.Error(t, mgr.CurrPlayer().ID(), bob.ID(), "current player")
+
+	utils.Fatal(t, bob.Play(Hand{NewCard(Nine, Hearts)}), nil)
+	utils.Error(t, mgr.Count(), 99, "count")
+
+	charlie := players[2]
+	charlie.ReplaceHand(Hand{NewCard(Four, Diamonds)})
+	utils.Fatal(t, charlie.Play(Hand{NewCard(Four, Diamonds)}), nil)
+	utils.Error(t, mgr.Table().Direction(), -1, "direction")
+	utils.Error(t, mgr.CurrPlayer().ID(), bob.ID(), "current player")
+
+	utils.Fatal(t, bob.Play(Hand{NewCard(Jack, Hearts)}), nil)
+	utils.Error(t, mgr.Playing(), true, "playing after a bust")
+	utils.Error(t, mgr.Lives(bob.ID()), 2, "lives after a bust")
+	utils.Error(t, mgr.Round(), 2, "round after a bust")
+	utils.Error(t, mgr.CurrPlayer().ID(), charlie.ID(), "player leading the next round")
+	utils.Error(t, mgr.Table().Direction(), 1, "direction in the next round")
+	utils.Error(t, len(bob.Hand()), 3, "cards dealt in the next round")
+
+	history := mgr.Events().History()
+	var types []EventType
+	for _, e := range history[len(history)-3:] {
+		types = append(types, e.Type)
+	}
+	utils.Error(t, types, []EventType{EventBusted, EventLifeLost, EventDealt})
+}
+
+func TestEliminationAndWin(t *testing.T) {
+	settings := *NNDefaultSettings
+	settings.LivesPerPlayer = 1
+	mgr := new(NNGameManager)
+	players := []*NNPlayer{NewNNPlayer("Alice"), NewNNPlayer("Bob"), NewNNPlayer("Charlie")}
+	mgr.StartGame(players, 0, &settings)
+	alice, bob, charlie := players[0], players[1], players[2]
+
+	mgr.count = 95
+	alice.ReplaceHand(Hand{NewCard(Queen, Hearts)})
+	utils.Fatal(t, alice.Play(Hand{NewCard(Queen, Hearts)}), nil)
+	utils.Error(t, mgr.Table().Active(alice.ID()), false, "alice active after losing her last life")
+	utils.Error(t, len(alice.Hand()), 0, "cards held by an eliminated player")
+	utils.Error(t, mgr.CurrPlayer().ID(), bob.ID(), "player leading the next round")
+
+	mgr.count = 95
+	bob.ReplaceHand(Hand{NewCard(Seven, Hearts)})
+	utils.Fatal(t, bob.Play(Hand{NewCard(Seven, Hearts)}), nil)
+	utils.Error(t, mgr.Playing(), false, "playing with one player left")
+	winner, ok := mgr.Winner()
+	utils.Fatal(t, ok, true, "winner declared")
+	utils.Error(t, winner.ID(), charlie.ID())
+}
+
+func TestRobotsPlayToTheEnd(t *testing.T) {
+	mgr := new(NNGameManager)
+	mgr.SetRandomizer(NewRngAt(2021))
+	mgr.StartGame(nil, 4, nil)
+	for turns := 0; mgr.Playing(); turns++ {
+		utils.Fatal(t, turns < 10000, true, "game finished in a reasonable number of turns")
+		utils.Fatal(t, mgr.PlayTurn(context.Background()), nil)
+	}
+	_, ok := mgr.Winner()
+	utils.Error(t, ok, true, "winner declared")
+}
+
+func TestView(t *testing.T) {
+	mgr, players := newTestGame("Alice", "Bob")
+	players[0].ReplaceHand(Hand{NewCard(Five, Hearts), NewCard(Nine, Clubs)})
+	mgr.count = 97
+
+	v := mgr.View(0)
+	utils.Error(t, v.Hand, players[0].Hand())
+	utils.Error(t, v.Count, 97)
+	utils.Error(t, v.Lives, []int{3, 3})
+	utils.Error(t, v.HandSizes, []int{2, 3})
+	utils.Error(t, v.Safe(NewCard(Five, Hearts)), false, "five at 97 safe")
+	utils.Error(t, v.Safe(NewCard(Nine, Clubs)), true, "nine at 97 safe")
+
+	v.Hand[0] = NewCard(King, Spades)
+	utils.Error(t, players[0].Hand()[0], NewCard(Five, Hearts), "after changing the view")
+}
+
+type ScoreResult struct {
+	count int
+	rank  Rank
+	want  int
+}
+
+func TestScoreRank(t *testing.T) {
+	tests := map[string]ScoreResult{
+		"ace":            {0, Ace, 1},
+		"value card":     {0, Seven, 7},
+		"face card":      {0, Queen, 10},
+		"reverse":        {20, Four, 0},
+		"zero":           {20, King, 0},
+		"minus ten":      {20, Ten, -10},
+		"ninety-nine":    {20, Nine, 79},
+		"ninety-nine 99": {99, Nine, 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mgr := &NNGameManager{settings: NNDefaultSettings, count: test.count}
+			got, err := mgr.ScoreRank(test.rank)
+			utils.Fatal(t, err, nil)
+			utils.Error(t, got, test.want)
+		})
+	}
+}
+
+func TestUndo(t *testing.T) {
+	mgr, players := newTestGame("Alice", "Bob", "Charlie")
+	alice, bob := players[0], players[1]
+	alice.ReplaceHand(Hand{NewCard(Five, Hearts), NewCard(Four, Clubs), NewCard(Nine, Spades)})
+	bob.ReplaceHand(Hand{NewCard(Jack, Hearts), NewCard(Ten, Clubs), NewCard(Nine, Hearts)})
+	mgr.count = 50
+
+	utils.Fatal(t, alice.Play(Hand{NewCard(Four, Clubs)}), nil)
+	utils.Error(t, mgr.Undo(alice) != nil, true, "error when take-backs are not allowed")
+
+	mgr, players = newTestGame("Alice", "Bob", "Charlie")
+	alice, bob = players[0], players[1]
+	mgr.settings = &NNGameSettings{}
+	*mgr.settings = *NNDefaultSettings
+	mgr.settings.Undo = true
+	alice.ReplaceHand(Hand{NewCard(Five, Hearts), NewCard(Four, Clubs), NewCard(Nine, Spades)})
+	bob.ReplaceHand(Hand{NewCard(Jack, Hearts), NewCard(Ten, Clubs), NewCard(Nine, Hearts)})
+	mgr.count = 50
+	before := mgr.dealer.copy()
+
+	utils.Error(t, mgr.Undo(alice) != nil, true, "error with nothing to take back")
+	utils.Fatal(t, alice.Play(Hand{NewCard(Four, Clubs)}), nil)
+	utils.Error(t, mgr.Table().Direction(), -1, "direction after reversing")
+	utils.Error(t, mgr.CanUndo(bob), false, "another player can take back the card")
+	utils.Error(t, mgr.Undo(bob) != nil, true, "error taking back another player's card")
+
+	utils.Error(t, mgr.CanUndo(alice), true, "the player can take back their card")
+	utils.Fatal(t, mgr.Undo(alice), nil)
+	utils.Error(t, mgr.Count(), 50, "count")
+	utils.Error(t, mgr.Table().Direction(), 1, "direction")
+	utils.Error(t, alice.Hand(), Hand{NewCard(Five, Hearts), NewCard(Four, Clubs), NewCard(Nine, Spades)}, "hand")
+	utils.Error(t, mgr.dealer.drawPile(), before.drawPile(), "draw pile")
+	utils.Error(t, mgr.dealer.discard, before.discard, "discard pile")
+	utils.Error(t, mgr.CurrPlayer().ID(), alice.ID(), "current player")
+	history := mgr.Events().History()
+	utils.Error(t, history[len(history)-1].Type, EventUndone, "latest event")
+	utils.Error(t, mgr.Undo(alice) != nil, true, "error taking back a card twice")
+
+	utils.Fatal(t, alice.Play(Hand{NewCard(Five, Hearts)}), nil)
+	utils.Fatal(t, bob.Play(Hand{NewCard(Ten, Clubs)}), nil)
+	utils.Error(t, mgr.Undo(alice) != nil, true, "error once the next player has acted")
+
+	mgr.count = 95
+	players[2].ReplaceHand(Hand{NewCard(Queen, Spades)})
+	utils.Fatal(t, players[2].Play(Hand{NewCard(Queen, Spades)}), nil)
+	utils.Error(t, mgr.Round(), 2, "round after a bust")
+	utils.Error(t, mgr.CanUndo(players[2]), false, "a bust cannot be taken back")
+}
@@ -0,0 +1,176 @@
+package cards
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// rankNames maps every accepted spelling of a rank, in lower case, to its Rank.
+var rankNames = map[string]Rank{
+	"1": Ace, "a": Ace, "ace": Ace,
+	"2": Two, "two": Two, "deuce": Two,
+	"3": Three, "three": Three,
+	"4": Four, "four": Four,
+	"5": Five, "five": Five,
+	"6": Six, "six": Six,
+	"7": Seven, "seven": Seven,
+	"8": Eight, "eight": Eight,
+	"9": Nine, "nine": Nine,
+	"10": Ten, "t": Ten, "ten": Ten,
+	"j": Jack, "jack": Jack,
+	"q": Queen, "queen": Queen,
+	"k": King, "king": King,
+}
+
+// suitNames maps every accepted spelling of a suit, in lower case, to its Suit.
+var suitNames = map[string]Suit{
+	"♣": Clubs, "♧": Clubs, "c": Clubs, "club": Clubs, "clubs": Clubs,
+	"♦": Diamonds, "♢": Diamonds, "d": Diamonds, "diamond": Diamonds, "diamonds": Diamonds,
+	"♥": Hearts, "♡": Hearts, "h": Hearts, "heart": Hearts, "hearts": Hearts,
+	"♠": Spades, "♤": Spades, "s": Spades, "spade": Spades, "spades": Spades,
+}
+
+// ansi matches the terminal escape codes used to colour cards.
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// ParseRank converts a rank's symbol (e.g. "Q", "10", "T") or long name (e.g. "Queen") to a Rank.
+// Parsing is case-insensitive.
+func ParseRank(s string) (Rank, error) {
+	if r, ok := rankNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return r, nil
+	}
+	return "", errors.Errorf("invalid rank %q", s)
+}
+
+// ParseSuit converts a suit's symbol (e.g. "♥"), letter (e.g. "H") or long name (e.g. "Hearts") to a Suit.
+// Parsing is case-insensitive.
+func ParseSuit(s string) (Suit, error) {
+	if suit, ok := suitNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return suit, nil
+	}
+	return "", errors.Errorf("invalid suit %q", s)
+}
+
+// ParseCard converts a card's text representation to a Card.
+// It accepts the compact form produced by String (e.g. "10♥"), suit letters (e.g. "10H", "TH"),
+// long names (e.g. "Ten of Hearts") and hyphenated names (e.g. "ten-hearts").
+func ParseCard(s string) (Card, error) {
+	str := strings.TrimSpace(ansi.ReplaceAllString(s, ""))
+	var rank, suit string
+	if parts := strings.Fields(str); len(parts) == 3 && strings.EqualFold(parts[1], "of") {
+		rank, suit = parts[0], parts[2]
+	} else if parts := strings.Split(str, "-"); len(parts) == 2 {
+		rank, suit = parts[0], parts[1]
+	} else if len(str) > 0 {
+		_, size := utf8.DecodeLastRuneInString(str)
+		rank, suit = str[:len(str)-size], str[len(str)-size:]
+	}
+
+	r, err := ParseRank(rank)
+	if err != nil {
+		return Card{}, errors.Wrapf(err, "invalid card %q", s)
+	}
+	su, err := ParseSuit(suit)
+	if err != nil {
+		return Card{}, errors.Wrapf(err, "invalid card %q", s)
+	}
+	return NewCard(r, su), nil
+}
+
+// parseCards converts a list of cards to a Card slice.
+// Cards may be separated by commas or whitespace, and the list may be enclosed in brackets,
+// so the output of Hand.String and Shoe.String is accepted.
+func parseCards(s string) ([]Card, error) {
+	str := strings.TrimSpace(ansi.ReplaceAllString(s, ""))
+	str = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(str, "["), "]"))
+	if str == "" {
+		return []Card{}, nil
+	}
+
+	var fields []string
+	if strings.Contains(str, ",") {
+		fields = strings.Split(str, ",")
+	} else if words := strings.Fields(str); len(words) == 3 && strings.EqualFold(words[1], "of") {
+		fields = []string{str}
+	} else {
+		fields = words
+	}
+
+	cards := make([]Card, len(fields))
+	for i, field := range fields {
+		c, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards[i] = c
+	}
+	return cards, nil
+}
+
+// ParseHand converts a list of cards, such as "[10♥, J♠]" or "10H JS", to a Hand.
+func ParseHand(s string) (Hand, error) {
+	cards, err := parseCards(s)
+	return Hand(cards), err
+}
+
+// ParseShoe converts a list of cards, such as "[10♥, J♠]" or "10H JS", to a Shoe.
+func ParseShoe(s string) (Shoe, error) {
+	cards, err := parseCards(s)
+	return Shoe(cards), err
+}
+
+// MarshalText encodes the rank as its symbol.
+func (r Rank) MarshalText() ([]byte, error) {
+	if _, err := ParseRank(string(r)); err != nil {
+		return nil, err
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes any representation of a rank accepted by ParseRank.
+func (r *Rank) UnmarshalText(text []byte) error {
+	rank, err := ParseRank(string(text))
+	if err == nil {
+		*r = rank
+	}
+	return err
+}
+
+// MarshalText encodes the suit as its symbol.
+func (s Suit) MarshalText() ([]byte, error) {
+	if _, err := ParseSuit(string(s)); err != nil {
+		return nil, err
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes any representation of a suit accepted by ParseSuit.
+func (s *Suit) UnmarshalText(text []byte) error {
+	suit, err := ParseSuit(string(text))
+	if err == nil {
+		*s = suit
+	}
+	return err
+}
+
+// MarshalText encodes the card in the compact form produced by String, e.g. "10♥".
+func (c Card) MarshalText() ([]byte, error) {
+	if _, err := c.rank.MarshalText(); err != nil {
+		return nil, err
+	} else if _, err := c.suit.MarshalText(); err != nil {
+		return nil, err
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes any representation of a card accepted by ParseCard.
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err == nil {
+		*c = card
+	}
+	return err
+}
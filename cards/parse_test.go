@@ -0,0 +1,103 @@
+package cards
+
+import (
+	"encoding/json"
+	"shuffle/utils"
+	"testing"
+)
+
+type ParseCardResult struct {
+	text string
+	want Card
+	err  bool
+}
+
+func TestParseCard(t *testing.T) {
+	tests := map[string]ParseCardResult{
+		"symbol":              {"10♥", NewCard(Ten, Hearts), false},
+		"letter":              {"10H", NewCard(Ten, Hearts), false},
+		"short ten":           {"TH", NewCard(Ten, Hearts), false},
+		"long name":           {"Ten of Hearts", NewCard(Ten, Hearts), false},
+		"hyphenated":          {"ten-hearts", NewCard(Ten, Hearts), false},
+		"lower case":          {"qs", NewCard(Queen, Spades), false},
+		"face card symbol":    {"Q♠", NewCard(Queen, Spades), false},
+		"ace of clubs":        {"ace of clubs", NewCard(Ace, Clubs), false},
+		"padded":              {"  7♦ ", NewCard(Seven, Diamonds), false},
+		"coloured":            {NewCard(King, Diamonds).colourString(), NewCard(King, Diamonds), false},
+		"invalid rank":        {"14♥", Card{}, true},
+		"invalid suit":        {"10X", Card{}, true},
+		"invalid long suit":   {"Ten of Elephants", Card{}, true},
+		"empty":               {"", Card{}, true},
+		"rank only":           {"10", Card{}, true},
+		"hyphen without rank": {"-hearts", Card{}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseCard(test.text)
+			utils.Error(t, err != nil, test.err, "error")
+			utils.Error(t, got, test.want)
+		})
+	}
+}
+
+func TestParseHand(t *testing.T) {
+	want := Hand{NewCard(Ten, Hearts), NewCard(Jack, Spades), NewCard(Ace, Diamonds)}
+	tests := map[string]string{
+		"string output":   want.String(),
+		"commas":          "10♥, J♠, A♦",
+		"whitespace":      "10H JS AD",
+		"long names":      "Ten of Hearts, Jack of Spades, Ace of Diamonds",
+		"mixed notations": "[TH, jack-spades, A♦]",
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseHand(text)
+			utils.Fatal(t, err, nil)
+			utils.Error(t, got, want)
+		})
+	}
+
+	single, err := ParseHand("Queen of Clubs")
+	utils.Fatal(t, err, nil)
+	utils.Error(t, single, Hand{NewCard(Queen, Clubs)}, "single long name")
+
+	empty, err := ParseHand("[]")
+	utils.Fatal(t, err, nil)
+	utils.Error(t, len(empty), 0, "cards in empty hand")
+
+	_, err = ParseHand("10H, XX")
+	utils.Error(t, err != nil, true, "error parsing an invalid card")
+}
+
+func TestParseShoeRoundTrip(t *testing.T) {
+	shoe := NewShoe(2)
+	got, err := ParseShoe(shoe.String())
+	utils.Fatal(t, err, nil)
+	utils.Error(t, got, shoe)
+}
+
+func TestCardTextMarshaling(t *testing.T) {
+	h := Hand{NewCard(Ten, Hearts), NewCard(Queen, Spades)}
+	b, err := json.Marshal(h)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, string(b), `["10♥","Q♠"]`)
+
+	var got Hand
+	utils.Fatal(t, json.Unmarshal([]byte(`["10H","Queen of Spades"]`), &got), nil)
+	utils.Error(t, got, h)
+
+	_, err = json.Marshal(NewCard("14", Hearts))
+	utils.Error(t, err != nil, true, "error marshaling an invalid card")
+
+	var r Rank
+	utils.Fatal(t, r.UnmarshalText([]byte("king")), nil)
+	utils.Error(t, r, King)
+
+	var s Suit
+	utils.Fatal(t, s.UnmarshalText([]byte("D")), nil)
+	utils.Error(t, s, Diamonds)
+	text, _ := s.MarshalText()
+	utils.Error(t, string(text), "♦")
+}
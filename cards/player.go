@@ -7,64 +7,141 @@ import (
 // Player is an interface for entities that perform actions on Hands.
 // Player actions include playing a subset of a Hand or replacing an entire Hand.
 type Player interface {
-	Play(h Hand)
+	ID() int
+	Name() string
+	Hand() Hand
+	Play(h Hand) error
 	// TODO: Implement pick-up, handling concurrent requests on the deck from players.
 	// PickUp(c Card)
 	AcceptCards(h Hand)
 	ReplaceHand(h Hand)
+	join(id int, mgr GameManager)
+	removeCard(c Card) bool
 }
 
-// NNPlayer is an implementation of Player designed to play the 99 card game.
-// It plays a single card per turn.
-type NNPlayer struct {
-	id   int // TODO: make this a UUID
-	Name string
-	mgr  *NNGameManager
+// player implements the parts of a Player that are common to every game.
+// Game-specific players embed it.
+type player struct {
+	id   int
+	name string
+	mgr  GameManager
 	hand Hand
 }
 
-// NewNNPlayer creates a 99 player with the given name.
-func NewNNPlayer(name string) *NNPlayer {
-	p := new(NNPlayer)
-	p.Name = name
-	return p
+// ID returns the Player's seat number at their Table.
+func (p *player) ID() int {
+	return p.id
 }
 
-// selectCardAt selects the card at index i.
-// It returns an error if i is invalid.
-func (p *NNPlayer) selectCardAt(i int) (c Card, err error) {
-	if 0 <= i && i < len(p.hand) {
-		return p.hand[i], nil
-	} else {
-		return Card{}, errors.Wrap(err, "invalid card selected")
-	}
+// Name returns the Player's display name.
+func (p *player) Name() string {
+	return p.name
 }
 
-// playCardAt plays the card at index i.
-// An error is returned if i is invalid or the card played is invalid.
-// It is the default card selection mechanism for the command line version of the game.
-func (p *NNPlayer) playCardAt(i int) error {
-	h := make(Hand, 0)
-	c, err := p.selectCardAt(i)
-	if err != nil {
-		return err
-	}
-	h = append(h, c)
-	return p.Play(h)
+// Hand returns a copy of the Player's Hand.
+func (p *player) Hand() Hand {
+	h := make(Hand, len(p.hand))
+	copy(h, p.hand)
+	return h
 }
 
 // Play submits a Player's Hand to the GameManager.
 // The GameManager returns an error if the play is invalid, which is forwarded along.
-func (p *NNPlayer) Play(h Hand) error {
+func (p *player) Play(h Hand) error {
+	if p.mgr == nil {
+		return errors.New("not seated at a table")
+	}
 	return p.mgr.Play(p, h)
 }
 
 // AcceptCards adds more Cards to a Player's existing Hand.
-func (p *NNPlayer) AcceptCards(h Hand) {
+func (p *player) AcceptCards(h Hand) {
 	p.hand = append(p.hand, h...)
 }
 
 // ReplaceHand replaces a Player's existing Hand with an entirely new Hand.
-func (p *NNPlayer) ReplaceHand(h Hand) {
+func (p *player) ReplaceHand(h Hand) {
 	p.hand = h
 }
+
+// join seats the Player at a Table run by the GameManager.
+func (p *player) join(id int, mgr GameManager) {
+	p.id = id
+	p.mgr = mgr
+}
+
+// removeCard removes a Card from the Player's Hand, if it exists.
+// It returns false if the Card is not in the Player's Hand.
+func (p *player) removeCard(c Card) bool {
+	for i, card := range p.hand {
+		if card == c {
+			p.hand = append(p.hand[:i:i], p.hand[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// selectCardAt selects the card at index i of a Player's Hand.
+// It returns an error if i is invalid.
+func selectCardAt(p Player, i int) (Card, error) {
+	h := p.Hand()
+	if 0 <= i && i < len(h) {
+		return h[i], nil
+	}
+	return Card{}, errors.New("invalid card selected")
+}
+
+// playCardAt plays the card at index i of a Player's Hand.
+// An error is returned if i is invalid or the card played is invalid.
+// It is the default card selection mechanism for the command line version of each game.
+func playCardAt(p Player, i int) error {
+	c, err := selectCardAt(p, i)
+	if err != nil {
+		return err
+	}
+	return p.Play(Hand{c})
+}
+
+// NNPlayer is an implementation of Player designed to play the 99 card game.
+// It plays a single card per turn.
+// Robot players choose their cards with an NNStrategy; human players have none.
+type NNPlayer struct {
+	player
+	strategy NNStrategy
+	profile  UUID
+}
+
+// NewNNPlayer creates a 99 player with the given name.
+func NewNNPlayer(name string) *NNPlayer {
+	p := new(NNPlayer)
+	p.name = name
+	return p
+}
+
+// NewNNRobot creates a 99 robot player with the given name, which plays according to the strategy.
+func NewNNRobot(name string, s NNStrategy) *NNPlayer {
+	p := NewNNPlayer(name)
+	p.strategy = s
+	return p
+}
+
+// Robot returns true if the player's cards are chosen by a strategy rather than a person.
+func (p *NNPlayer) Robot() bool {
+	return p.strategy != nil
+}
+
+// Strategy returns the strategy a robot player uses to choose cards, or nil for a human player.
+func (p *NNPlayer) Strategy() NNStrategy {
+	return p.strategy
+}
+
+// Profile returns the ID of the player's persistent profile, or the zero UUID if they have none.
+func (p *NNPlayer) Profile() UUID {
+	return p.profile
+}
+
+// SetProfile links the player to a persistent profile, which records the games they play.
+func (p *NNPlayer) SetProfile(id UUID) {
+	p.profile = id
+}
@@ -2,16 +2,19 @@ package cards
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 )
 
 // =============
 // *** CARDS ***
 // =============
 
-// revealPile prints out all the remaining Cards in a Shoe.
-func revealPile(name string, s Shoe) {
-	fmt.Printf("%s pile: %d cards\n", name, len(s))
-	fmt.Println(s)
+// revealPile writes out all the remaining Cards in a Shoe, under a title that is given the number of cards.
+func revealPile(w io.Writer, title string, s Shoe) {
+	fmt.Fprintln(w, translate(title, len(s)))
+	fmt.Fprintln(w, s)
 }
 
 // longString displays a Rank in long form.
@@ -71,27 +74,67 @@ func (d *dealer) drawPile() Shoe {
 	return d.draw[d.drawIdx:]
 }
 
-// revealDeck prints out the remaining cards in the Dealer's draw pile.
-func (d *dealer) revealDeck() {
-	revealPile("Draw", d.drawPile())
+// revealDeck writes out the remaining cards in the Dealer's draw pile.
+func (d *dealer) revealDeck(w io.Writer) {
+	revealPile(w, "Draw pile: %d cards", d.drawPile())
 }
 
-// revealDiscard print out the remaining cards in the Dealer's discard pile.
-func (d *dealer) revealDiscard() {
-	revealPile("Discard", d.discard)
+// revealDiscard writes out the remaining cards in the Dealer's discard pile.
+func (d *dealer) revealDiscard(w io.Writer) {
+	revealPile(w, "Discard pile: %d cards", d.discard)
 }
 
-// revealDecks print out the remaining cards in the Dealer's draw and discard piles, respectively.
-func (d *dealer) revealDecks() {
-	d.revealDeck()
-	d.revealDiscard()
+// revealDecks writes out the remaining cards in the Dealer's draw and discard piles, respectively.
+func (d *dealer) revealDecks(w io.Writer) {
+	d.revealDeck(w)
+	d.revealDiscard(w)
 }
 
 // ==============
 // *** PLAYER ***
 // ==============
 
-// revealHand prints out the Player's Hand.
-func (p NNPlayer) revealHand() {
-	fmt.Printf("%v\t(p%v): \t%v\n", p.Name, p.id, p.hand.String())
+// revealHand writes out the Player's Hand.
+// Hands drawn over several lines start on the line after the Player's name.
+func revealHand(w io.Writer, p Player) {
+	hand := p.Hand().String()
+	if strings.Contains(hand, "\n") {
+		fmt.Fprintf(w, "%v\t(p%v):\n%v\n", p.Name(), p.ID(), hand)
+		return
+	}
+	fmt.Fprintf(w, "%v\t(p%v): \t%v\n", p.Name(), p.ID(), hand)
+}
+
+// ===============
+// *** MANAGER ***
+// ===============
+
+// revealPlayers writes out every Player's Hand.
+func revealPlayers(w io.Writer, t *Table) {
+	for _, p := range t.Players() {
+		revealHand(w, p)
+	}
+}
+
+// RevealTable shows all cards in the game, including the draw pile, discard pile,
+// and every player's hand.
+func (mgr NNGameManager) revealTable() {
+	mgr.writeTable(os.Stdout)
+}
+
+// writeTable writes out all cards in the game, including the draw pile, discard pile,
+// and every player's hand.
+func (mgr NNGameManager) writeTable(w io.Writer) {
+	fmt.Fprintln(w, translate("Count: %v", mgr.count))
+	revealPlayers(w, mgr.table)
+	mgr.dealer.revealDecks(w)
+}
+
+// RevealTable shows all cards in the game, including the draw pile, discard pile,
+// and every player's hand.
+func (mgr CEGameManager) revealTable() {
+	top, _ := mgr.Top()
+	say("Top: %v", top.colourString())
+	revealPlayers(os.Stdout, mgr.table)
+	mgr.dealer.revealDecks(os.Stdout)
 }
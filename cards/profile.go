@@ -0,0 +1,370 @@
+package cards
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UUID is a universally unique identifier, used to recognize a player from one game to the next.
+type UUID [16]byte
+
+// NewUUID generates a random (version 4) UUID.
+func NewUUID() (UUID, error) {
+	var id UUID
+	if _, err := rand.Read(id[:]); err != nil {
+		return id, errors.Wrap(err, "could not generate UUID")
+	}
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return id, nil
+}
+
+// ParseUUID converts the canonical text form of a UUID, e.g. "f47ac10b-58cc-4372-a567-0e02b2c3d479", to a UUID.
+func ParseUUID(s string) (UUID, error) {
+	var id UUID
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != len(id) || len(s) != 36 {
+		return id, errors.Errorf("invalid UUID %q", s)
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// String converts the UUID to its canonical text form.
+func (id UUID) String() string {
+	h := hex.EncodeToString(id[:])
+	return h[:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
+
+// MarshalText encodes the UUID in its canonical text form.
+func (id UUID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText decodes the canonical text form of a UUID.
+func (id *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err == nil {
+		*id = parsed
+	}
+	return err
+}
+
+// Profile is the record of a person's 99 games, kept from one game to the next.
+type Profile struct {
+	ID          UUID         `json:"id"`
+	Name        string       `json:"name"`
+	Games       int          `json:"games"`
+	Wins        int          `json:"wins"`
+	Busts       int          `json:"busts"`
+	BustsCaused int          `json:"busts_caused"` // the number of times the next player busted straight after their card
+	Plays       map[Rank]int `json:"plays"`        // the number of cards of each rank they have played
+	Rating      float64      `json:"rating"`
+	Updated     time.Time    `json:"updated"`
+}
+
+// Favorite returns the rank the player has played most often, preferring higher ranks in a tie.
+// It returns false if they have not played any cards.
+func (p Profile) Favorite() (Rank, bool) {
+	var best Rank
+	for _, r := range Ranks {
+		if n := p.Plays[r]; n > 0 && n >= p.Plays[best] {
+			best = r
+		}
+	}
+	return best, best != ""
+}
+
+// ProfileStore keeps player profiles in a local JSON Lines file.
+// Every change appends the whole updated profile to the file, and the latest line for each player wins,
+// so an interrupted write can never lose more than the latest game.
+type ProfileStore struct {
+	mu       sync.Mutex
+	path     string
+	profiles map[UUID]*Profile
+	order    []UUID // in the order profiles were created
+	torn     bool   // whether the file ends in a line left unfinished by an interrupted write
+	err      error  // the first error saving the outcome of a tracked game
+	now      func() time.Time
+}
+
+// DefaultProfilePath returns where profiles are kept unless configured otherwise:
+// profiles.jsonl in the shuffle directory of the user's configuration directory.
+func DefaultProfilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = "."
+	}
+	return filepath.Join(dir, "shuffle", "profiles.jsonl")
+}
+
+// OpenProfileStore loads the profiles kept at the given path.
+// The file is created the first time a profile is saved, if it does not already exist.
+// A line that cannot be read is taken to be an interrupted write, and skipped.
+func OpenProfileStore(path string) (*ProfileStore, error) {
+	s := &ProfileStore{path: path, profiles: make(map[UUID]*Profile), now: time.Now}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "could not open profiles")
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		p := new(Profile)
+		err := json.Unmarshal(sc.Bytes(), p)
+		s.torn = err != nil
+		if err != nil {
+			continue
+		}
+		if _, ok := s.profiles[p.ID]; !ok {
+			s.order = append(s.order, p.ID)
+		}
+		s.profiles[p.ID] = p
+	}
+	if err := sc.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read profiles")
+	}
+	return s, nil
+}
+
+// Get returns the profile with the given ID.
+// It returns false if there is no such profile.
+func (s *ProfileStore) Get(id UUID) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.profiles[id]; ok {
+		return p.copy(), true
+	}
+	return Profile{}, false
+}
+
+// Find returns the earliest created profile with the given name, ignoring case.
+// It returns false if there is no such profile.
+func (s *ProfileStore) Find(name string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range s.order {
+		if p := s.profiles[id]; strings.EqualFold(p.Name, name) {
+			return p.copy(), true
+		}
+	}
+	return Profile{}, false
+}
+
+// Create saves a new profile for a player with the given name.
+func (s *ProfileStore) Create(name string) (Profile, error) {
+	id, err := NewUUID()
+	if err != nil {
+		return Profile{}, err
+	}
+	p := Profile{ID: id, Name: name, Plays: make(map[Rank]int), Rating: initialRating}
+	return p, s.Save(p)
+}
+
+// FindOrCreate returns the profile with the given name, creating it if there is none.
+func (s *ProfileStore) FindOrCreate(name string) (Profile, error) {
+	if p, ok := s.Find(name); ok {
+		return p, nil
+	}
+	return s.Create(name)
+}
+
+// Save records the profile, replacing any earlier version of it.
+func (s *ProfileStore) Save(p Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(p)
+}
+
+// save records the profile; the store must be locked.
+func (s *ProfileStore) save(p Profile) error {
+	p.Updated = s.now()
+	line, err := json.Marshal(p)
+	if err != nil {
+		return errors.Wrap(err, "could not encode profile")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return errors.Wrap(err, "could not create profile directory")
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "could not open profiles")
+	}
+	defer f.Close()
+	if s.torn {
+		line = append([]byte{'\n'}, line...)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "could not save profile")
+	}
+	s.torn = false
+
+	if _, ok := s.profiles[p.ID]; !ok {
+		s.order = append(s.order, p.ID)
+	}
+	saved := p.copy()
+	s.profiles[p.ID] = &saved
+	return nil
+}
+
+// Leaderboard returns every profile, best rated first, breaking ties by wins and then by name.
+func (s *ProfileStore) Leaderboard() []Profile {
+	s.mu.Lock()
+	profiles := make([]Profile, 0, len(s.order))
+	for _, id := range s.order {
+		profiles = append(profiles, s.profiles[id].copy())
+	}
+	s.mu.Unlock()
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		a, b := profiles[i], profiles[j]
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		} else if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Name < b.Name
+	})
+	return profiles
+}
+
+// WriteLeaderboard writes the leaderboard as a human-readable table.
+func (s *ProfileStore) WriteLeaderboard(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tplayer\trating\tgames\twins\tbusts\tbusts caused\tfavorite card")
+	for i, p := range s.Leaderboard() {
+		favorite := "-"
+		if r, ok := p.Favorite(); ok {
+			favorite = r.localString()
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%.0f\t%d\t%d\t%d\t%d\t%s\n",
+			i+1, p.Name, p.Rating, p.Games, p.Wins, p.Busts, p.BustsCaused, favorite)
+	}
+	return tw.Flush()
+}
+
+// copy returns a deep copy of the profile.
+func (p *Profile) copy() Profile {
+	c := *p
+	c.Plays = make(map[Rank]int, len(p.Plays))
+	for r, n := range p.Plays {
+		c.Plays[r] = n
+	}
+	return c
+}
+
+// Track records the outcome of the game run by the manager in the profiles of its players,
+// once the game has been won. Only players given a profile with SetProfile are recorded,
+// and abandoned games are not recorded at all. Any error saving them is reported by Err.
+// It must be called after the manager's event stream is created and before the game begins.
+func (s *ProfileStore) Track(mgr *NNGameManager) {
+	t := &profileTracker{store: s, game: newSimRecorder(mgr), plays: make(map[int]map[Rank]int),
+		busts: make(map[int]int), caused: make(map[int]int), previous: NoSeat, latest: NoSeat}
+	mgr.Events().Subscribe(t.record)
+}
+
+// Err returns the first error met while saving the outcome of a game tracked with Track, if any.
+func (s *ProfileStore) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// profileTracker tallies the events of a single game on behalf of a ProfileStore.
+type profileTracker struct {
+	store    *ProfileStore
+	game     *simRecorder
+	plays    map[int]map[Rank]int
+	busts    map[int]int
+	caused   map[int]int
+	previous int // the seat that played the card before the latest one this round
+	latest   int
+}
+
+// record tallies a single Event, and saves the players' profiles at the end of a won game.
+// A bust is blamed on the player who played the card just before the busting card.
+func (t *profileTracker) record(e Event) {
+	t.game.record(e)
+	switch e.Type {
+	case EventDealt:
+		t.previous, t.latest = NoSeat, NoSeat
+	case EventCardPlayed:
+		t.previous, t.latest = t.latest, e.Seat
+		if t.plays[e.Seat] == nil {
+			t.plays[e.Seat] = make(map[Rank]int)
+		}
+		t.plays[e.Seat][e.Cards[0].rank]++
+	case EventUndone:
+		t.plays[e.Seat][e.Cards[0].rank]--
+		t.latest = t.previous
+	case EventBusted:
+		t.busts[e.Seat]++
+		if t.previous != NoSeat && t.previous != e.Seat {
+			t.caused[t.previous]++
+		}
+	case EventGameEnded:
+		if t.game.winner != NoSeat {
+			t.save()
+		}
+	}
+}
+
+// save updates the profile of every player in the game who has one.
+// Ratings are updated with the same multiplayer Elo as tournaments, and players without a profile
+// are rated as newcomers.
+func (t *profileTracker) save() {
+	s, mgr := t.store, t.game.mgr
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	players := mgr.Table().Players()
+	table := make([]*entrant, len(players))
+	profiles := make([]*Profile, len(players))
+	for seat, p := range players {
+		table[seat] = &entrant{rating: initialRating}
+		if nn, ok := p.(*NNPlayer); ok {
+			if prof, ok := s.profiles[nn.profile]; ok {
+				profiles[seat] = prof
+				table[seat].rating = prof.Rating
+			}
+		}
+	}
+	rate(table, t.game.places(len(players)))
+
+	for seat, prof := range profiles {
+		if prof == nil {
+			continue
+		}
+		p := prof.copy()
+		p.Games++
+		p.Wins += table[seat].wins
+		p.Busts += t.busts[seat]
+		p.BustsCaused += t.caused[seat]
+		for r, n := range t.plays[seat] {
+			p.Plays[r] += n
+		}
+		p.Rating = table[seat].rating
+		if err := s.save(p); err != nil && s.err == nil {
+			s.err = err
+		}
+	}
+}
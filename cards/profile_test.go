@@ -0,0 +1,143 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"shuffle/utils"
+	"strings"
+	"testing"
+)
+
+func TestUUID(t *testing.T) {
+	id, err := NewUUID()
+	utils.Fatal(t, err, nil)
+	utils.Error(t, id.String()[14], byte('4'), "version")
+	parsed, err := ParseUUID(id.String())
+	utils.Fatal(t, err, nil)
+	utils.Error(t, parsed, id)
+
+	other, _ := NewUUID()
+	utils.Error(t, other != id, true, "UUIDs are unique")
+
+	for _, s := range []string{"", "not-a-uuid", "f47ac10b58cc4372a5670e02b2c3d479", "f47ac10b-58cc-4372-a567-0e02b2c3d47"} {
+		_, err := ParseUUID(s)
+		utils.Error(t, err != nil, true, "error for "+s)
+	}
+}
+
+func TestProfileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles", "profiles.jsonl")
+	s, err := OpenProfileStore(path)
+	utils.Fatal(t, err, nil)
+
+	alice, err := s.FindOrCreate("Alice")
+	utils.Fatal(t, err, nil)
+	utils.Error(t, alice.Rating, initialRating, "rating for a newcomer")
+	bob, err := s.Create("Bob")
+	utils.Fatal(t, err, nil)
+	again, err := s.FindOrCreate("alice")
+	utils.Fatal(t, err, nil)
+	utils.Error(t, again.ID, alice.ID, "existing profile found ignoring case")
+
+	bob.Wins, bob.Rating = 3, 1600
+	bob.Plays[Nine] = 2
+	utils.Fatal(t, s.Save(bob), nil)
+
+	reopened, err := OpenProfileStore(path)
+	utils.Fatal(t, err, nil)
+	got, ok := reopened.Get(bob.ID)
+	utils.Fatal(t, ok, true, "profile after reopening")
+	utils.Error(t, got.Wins, 3, "latest version of the profile")
+	fav, ok := got.Favorite()
+	utils.Error(t, fav, Nine, "favorite card")
+	utils.Error(t, ok, true)
+
+	board := reopened.Leaderboard()
+	utils.Fatal(t, len(board), 2, "profiles on the leaderboard")
+	utils.Error(t, board[0].Name, "Bob", "leader")
+
+	var b bytes.Buffer
+	utils.Fatal(t, reopened.WriteLeaderboard(&b), nil)
+	utils.Error(t, strings.Contains(b.String(), "Nine"), true, "favorite card on the leaderboard")
+}
+
+func TestProfileStoreTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.jsonl")
+	s, err := OpenProfileStore(path)
+	utils.Fatal(t, err, nil)
+	alice, err := s.Create("Alice")
+	utils.Fatal(t, err, nil)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	utils.Fatal(t, err, nil)
+	f.WriteString(`{"id":"` + alice.ID.String() + `","name":"Ali`)
+	f.Close()
+
+	s, err = OpenProfileStore(path)
+	utils.Fatal(t, err, nil, "a truncated line is skipped")
+	got, ok := s.Get(alice.ID)
+	utils.Error(t, ok, true)
+	utils.Error(t, got.Name, "Alice", "the last complete profile")
+	bob, err := s.Create("Bob")
+	utils.Fatal(t, err, nil)
+
+	s, err = OpenProfileStore(path)
+	utils.Fatal(t, err, nil, "saving after a truncated line")
+	_, ok = s.Get(bob.ID)
+	utils.Error(t, ok, true, "profile saved after a truncated line")
+
+	got, _ = s.Get(alice.ID)
+	utils.Error(t, got.Name, "Alice", "profile saved before a truncated line")
+}
+
+func TestProfileTrack(t *testing.T) {
+	s, err := OpenProfileStore(filepath.Join(t.TempDir(), "profiles.jsonl"))
+	utils.Fatal(t, err, nil)
+	var players []*NNPlayer
+	for i, name := range []string{"Alice", "Bob", "Charlie"} {
+		p := NewNNRobot(name, NewRandomStrategy(int64(i)))
+		if name != "Charlie" {
+			prof, _ := s.Create(name)
+			p.SetProfile(prof.ID)
+		}
+		players = append(players, p)
+	}
+
+	mgr := new(NNGameManager)
+	mgr.SetRandomizer(NewRngAt(2021))
+	mgr.events = NewEventStream()
+	s.Track(mgr)
+	mgr.StartGame(players, 0, nil)
+	for mgr.Playing() {
+		utils.Fatal(t, mgr.PlayTurn(context.Background()), nil)
+	}
+
+	winner, _ := mgr.Winner()
+	busts := make(map[int]int)
+	for _, e := range mgr.Events().History() {
+		if e.Type == EventBusted {
+			busts[e.Seat]++
+		}
+	}
+	for _, p := range players[:2] {
+		prof, _ := s.Get(p.Profile())
+		utils.Error(t, prof.Games, 1, p.Name()+" games")
+		utils.Error(t, prof.Wins == 1, winner.ID() == p.ID(), p.Name()+" won")
+		utils.Error(t, prof.Rating > initialRating, winner.ID() == p.ID(), p.Name()+" rating went up")
+		utils.Error(t, prof.Busts, busts[p.ID()], p.Name()+" busts")
+	}
+	utils.Error(t, len(s.Leaderboard()), 2, "no profile for a player without one")
+	utils.Error(t, s.Err(), nil)
+
+	s.path = filepath.Join(s.path, "not-a-directory")
+	mgr = new(NNGameManager)
+	mgr.SetRandomizer(NewRngAt(2022))
+	mgr.events = NewEventStream()
+	s.Track(mgr)
+	mgr.StartGame(players, 0, nil)
+	for mgr.Playing() {
+		utils.Fatal(t, mgr.PlayTurn(context.Background()), nil)
+	}
+	utils.Error(t, s.Err() != nil, true, "an error saving the game is reported")
+}
@@ -15,6 +15,11 @@ type Randomizer interface {
 	Shuffle(s Shoe)
 }
 
+// forker is a Randomizer that can split off a copy of itself, which shuffles independently but identically.
+type forker interface {
+	fork() Randomizer
+}
+
 type rng struct {
 	once sync.Once
 	r    *mrand.Rand
@@ -66,6 +71,14 @@ func (rng *rng) Randomize() {
 	}
 }
 
+// fork reseeds the random number generator from its own sequence, and returns a copy that will
+// shuffle exactly as it does from then on.
+func (rng *rng) fork() Randomizer {
+	seed := rng.r.Int63()
+	rng.r.Seed(seed)
+	return NewRngAt(seed)
+}
+
 // Shuffle randomly shuffles a Shoe.
 func (rng *rng) Shuffle(s Shoe) {
 	rng.r.Shuffle(len(s), func(i, j int) { s[j], s[i] = s[i], s[j] })
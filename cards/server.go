@@ -0,0 +1,590 @@
+package cards
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The 99 table protocol lets people play 99 over a plain TCP connection, such as with nc or telnet,
+// one line at a time. On connecting, the server asks for a name, which is answered on a line of its own:
+//
+//	Welcome to 99! What's your name?
+//	Alice
+//
+// Players then choose a table in the lobby:
+//
+//	tables                 lists the public tables
+//	new [seats] [private]  sets up a new table and sits down at it
+//	join <table>           sits down at a public table, or at a private one by its join code
+//	quick                  sits down at an open table, whose empty seats are filled by robots if nobody else comes
+//	resume <token>         returns to a seat after losing the connection
+//
+// and play at it:
+//
+//	hand           shows your hand
+//	play 2         plays the second card in your hand
+//	undo           takes back the card you just played, if the rules allow it and the next player has not acted
+//	count          shows the count
+//	who            lists the players, their lives and whose turn it is
+//	chat hi        says "hi" to everyone at the table
+//	emote gg       says one of the quick emotes, such as "Good game!"; emote alone lists them
+//	rules          shows the rules the table plays by
+//	leave          gives up your seat before the game begins
+//
+// The first person to sit down at a table is its host, who looks after it:
+//
+//	mute <name>           stops someone chatting; unmute lets them again
+//	kick <name> [robot]   removes someone from the table; once the game has begun, they are eliminated,
+//	                      or with robot, a robot plays on in their place
+//	pause                 pauses the game, stopping the turn timer; unpause lets play go on
+//	robot add             seats a robot, in an empty seat or, once the game has begun, when the next round is dealt;
+//	                      robot remove unseats one
+//	rules lives 5         changes a rule: lives, cards, max or undo; once the game has begun,
+//	                      lives are fixed and the other rules change from the next round
+//
+// "help" lists the commands and "quit" hangs up. Everything that happens at the table is written as it happens,
+// in the same words the command line uses, and on each of your turns you are shown the count and your hand.
+
+// clientBuffer is how many lines a connection may fall behind by before it is hung up,
+// so that a slow connection can never hold up a game.
+const clientBuffer = 1024
+
+// maxNameLength is the most characters a player's name may have.
+const maxNameLength = 20
+
+// ServeLobby lets people play 99 at the lobby's tables over connections to the listener,
+// speaking the 99 table protocol, until the context is cancelled.
+func ServeLobby(ctx context.Context, l net.Listener, lobby *Lobby) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go serveClient(ctx, conn, lobby)
+	}
+}
+
+// serveClient talks to a single connection until it hangs up, or the context is cancelled.
+func serveClient(ctx context.Context, conn net.Conn, lobby *Lobby) {
+	c := &client{
+		lobby:  lobby,
+		conn:   conn,
+		out:    make(chan string, clientBuffer),
+		closed: make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-c.closed:
+		}
+	}()
+	c.serve()
+}
+
+// client is a connection to a person playing 99 over the table protocol.
+// Its lines are read on one goroutine, and written on another so that writing never holds up a game.
+type client struct {
+	lobby  *Lobby
+	conn   net.Conn
+	out    chan string
+	closed chan struct{}
+	once   sync.Once
+	name   string
+	at     *following // the table the person is seated at, if any
+}
+
+// following is a person's seat at a table, and the Events of the table on their way to them.
+type following struct {
+	seat        *Seat
+	conn        connection // the connection to the seat this client holds
+	events      chan Event
+	unsubscribe func()
+	stop        chan struct{}
+}
+
+// serve reads commands from the connection until the person quits or hangs up.
+// If they are seated at a table, their seat is given up before the game begins,
+// or held for them to resume once it has begun.
+func (c *client) serve() {
+	defer c.close()
+	go c.write()
+
+	lines := bufio.NewScanner(c.conn)
+	c.send(translate("Welcome to 99! What's your name?"))
+	for c.name == "" {
+		if !lines.Scan() {
+			return
+		}
+		name := strings.TrimSpace(stripControl(lines.Text()))
+		if n := len([]rune(name)); n == 0 || n > maxNameLength {
+			c.send(translate("Names have 1-%d characters. What's your name?", maxNameLength))
+			continue
+		}
+		c.name = name
+	}
+	c.send(translate("Hello, %s! Enter help for the list of commands.", c.name))
+	for lines.Scan() {
+		if !c.handle(strings.TrimSpace(lines.Text())) {
+			return
+		}
+	}
+}
+
+// close hangs up, and gives up or holds the person's seat.
+func (c *client) close() {
+	if at := c.table(); at != nil {
+		at.seat.hangUp(at.conn.gen)
+		c.stand()
+	}
+	c.once.Do(func() {
+		close(c.closed)
+	})
+}
+
+// send queues a line to be written to the connection. It is safe to call from any goroutine,
+// including a table's, and hangs up if the connection has fallen too far behind.
+func (c *client) send(line string) {
+	select {
+	case c.out <- line:
+	default:
+		c.conn.Close()
+	}
+}
+
+// write writes the queued lines to the connection until it is closed, then writes any lines still queued.
+func (c *client) write() {
+	defer c.conn.Close()
+	for {
+		select {
+		case line := <-c.out:
+			if _, err := io.WriteString(c.conn, line+"\n"); err != nil {
+				return
+			}
+		case <-c.closed:
+			for {
+				select {
+				case line := <-c.out:
+					if _, err := io.WriteString(c.conn, line+"\n"); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// table returns the person's place at a table, unless they have none, the table has closed,
+// the host has kicked them from their seat or they have resumed it through another connection.
+func (c *client) table() *following {
+	if c.at == nil {
+		return nil
+	}
+	select {
+	case <-c.at.seat.Table().Done():
+		c.stand()
+		return nil
+	case <-c.at.conn.superseded:
+		c.stand()
+		return nil
+	default:
+		if !c.at.seat.Connected() {
+			c.stand()
+			return nil
+		}
+		return c.at
+	}
+}
+
+// handle carries out a single command. It returns false once the person has quit.
+func (c *client) handle(line string) bool {
+	cmd, arg := line, ""
+	if i := strings.IndexByte(line, ' '); i >= 0 {
+		cmd, arg = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	cmd = strings.ToLower(cmd)
+	at := c.table()
+	switch cmd {
+	case "":
+	case "help":
+		c.help()
+	case "quit":
+		c.send(translate("Goodbye!"))
+		return false
+	case "tables":
+		c.listTables()
+	case "new", "join", "quick", "resume":
+		if at != nil {
+			c.send(translate("You are already at table %s.", at.seat.Table().ID()))
+			break
+		}
+		c.sitDown(cmd, arg)
+	case "hand", "play", "undo", "count", "who", "chat", "emote", "mute", "unmute", "leave",
+		"pause", "unpause", "kick", "robot", "rules":
+		if at == nil {
+			c.send(translate("You are not at a table. Enter tables, new, join or quick to find one."))
+			break
+		}
+		c.play(at.seat, cmd, arg)
+	default:
+		c.send(translate("Unknown command %q. Enter help for the list of commands.", cmd))
+	}
+	return true
+}
+
+// help lists the commands.
+func (c *client) help() {
+	c.send(translate("In the lobby: tables, new [seats] [private], join <table or code>, quick, resume <token>"))
+	c.send(translate("At a table: hand, play <card>, undo, count, who, chat <message>, emote [name], rules, leave"))
+	c.send(translate("For the host: mute <name>, unmute <name>, kick <name> [robot], pause, unpause, robot add, robot remove, rules <rule> <value>"))
+	c.send(translate("At any time: help, quit"))
+}
+
+// listTables lists the public tables in the lobby.
+func (c *client) listTables() {
+	tables := c.lobby.Tables()
+	if len(tables) == 0 {
+		c.send(translate("There are no tables. Enter new to set one up, or quick to play now."))
+	}
+	for _, info := range tables {
+		line := translate("%s: %d/%d seats, %d lives, up to %d", info.ID, len(info.Players)+info.Robots, info.Seats,
+			info.Settings.LivesPerPlayer, info.Settings.MaxCount)
+		if len(info.Players) > 0 {
+			line += " - " + listNames(info.Players)
+		}
+		if info.Playing {
+			line += " " + translate("(playing)")
+		}
+		c.send(line)
+	}
+}
+
+// sitDown finds the person a seat at a table, by creating, joining or quick-matching a table,
+// or by resuming a seat that was held for them.
+func (c *client) sitDown(cmd, arg string) {
+	var seat *Seat
+	var missed []Event
+	var conn connection
+	var err error
+	switch cmd {
+	case "new":
+		opts := TableOptions{Seats: c.lobby.cfg.Seats}
+		for _, field := range strings.Fields(arg) {
+			if n, convErr := strconv.Atoi(field); convErr == nil {
+				opts.Seats = n
+			} else if strings.EqualFold(field, "private") {
+				opts.Private = true
+			}
+		}
+		var t *LobbyTable
+		if t, err = c.lobby.CreateTable(opts); err == nil {
+			if t.Code() != "" {
+				c.send(translate("Your private table's join code is %s.", t.Code()))
+			}
+			seat, err = t.sit(c.name)
+		}
+	case "join":
+		// Join codes are written in capitals, but are easily typed in lowercase.
+		if _, ok := c.lobby.Table(arg); !ok {
+			arg = strings.ToUpper(arg)
+		}
+		seat, err = c.lobby.Join(arg, c.name)
+	case "quick":
+		seat, err = c.lobby.QuickMatch(c.name, nil)
+	case "resume":
+		seat, missed, conn, err = c.lobby.reconnect(arg)
+	}
+	if err != nil {
+		c.send(translate("Error: %v", err))
+		return
+	}
+	if cmd != "resume" {
+		conn = seat.connection()
+	}
+
+	t := seat.Table()
+	at := &following{seat: seat, conn: conn, events: make(chan Event, clientBuffer), stop: make(chan struct{})}
+	at.unsubscribe = t.Events().Subscribe(func(e Event) {
+		select {
+		case at.events <- e:
+		default:
+			c.conn.Close()
+		}
+	})
+	history := t.Events().History()
+	if cmd == "resume" {
+		// Only the Events missed while disconnected are shown again, along with any since reconnecting.
+		history = missed
+		if len(missed) > 0 {
+			history = append(history, t.Events().Since(missed[len(missed)-1].Seq)...)
+		}
+	}
+	c.at = at
+	c.send(translate("You sit down at table %s. If you lose your connection, enter resume %s to return to your seat.", t.ID(), seat.Token()))
+	if info := t.Info(); !info.Playing {
+		c.send(translate("Table %s is waiting for %d more players.", t.ID(), info.Seats-len(info.Players)-info.Robots))
+	}
+	go c.follow(at, history, cmd == "resume")
+}
+
+// stand stops following the table the person was seated at.
+func (c *client) stand() {
+	c.at.unsubscribe()
+	close(c.at.stop)
+	c.at = nil
+}
+
+// follow writes what happens at the table: first the Events in the history, then every Event as it happens,
+// telling the person whenever it is their turn, until they stand up or the table closes.
+// People who resume their seat are shown their hand once they have caught up.
+func (c *client) follow(at *following, history []Event, resumed bool) {
+	t := at.seat.Table()
+	names := seatedNames(t.Events().History())
+	last := 0
+	show := func(e Event) {
+		last = e.Seq
+		switch e.Type {
+		case EventGameCreated:
+			names = e.Names
+			c.send(translate("The game begins: %v", listNames(names)))
+		case EventGameEnded:
+			c.send(sentence(translate("The game is over")))
+		default:
+			if e.Type == EventRobotAdded && len(e.Names) > 0 {
+				names = e.Names
+			}
+			if line, ok := describeNNEvent(names, e); ok {
+				c.send(line)
+			}
+		}
+	}
+	// drain shows the Events that have already happened, before anything that follows from them.
+	drain := func() {
+		for {
+			select {
+			case e := <-at.events:
+				if e.Seq > last {
+					show(e)
+				}
+			default:
+				return
+			}
+		}
+	}
+
+	for _, e := range history {
+		show(e)
+	}
+	if resumed {
+		drain()
+		if v, err := at.seat.View(); err == nil {
+			c.send(handLine(v.Hand))
+		}
+	}
+	for {
+		select {
+		case e := <-at.events:
+			if e.Seq > last {
+				show(e)
+			}
+		case <-at.seat.Turns():
+			drain()
+			if v, err := at.seat.View(); err == nil {
+				c.send(translate("Your turn! The count is %d.", v.Count))
+				c.send(handLine(v.Hand))
+			}
+		case <-t.Done():
+			drain()
+			c.send(translate("The table has closed. Enter tables, new, join or quick to play again."))
+			return
+		case <-at.conn.superseded:
+			c.send(translate("Your seat has been resumed from another connection."))
+			return
+		case <-at.stop:
+			return
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// seatedNames returns the names of the players in the game the Events are from, in seating order,
+// including any robots seated since it was created.
+func seatedNames(events []Event) []string {
+	var names []string
+	for _, e := range events {
+		if e.Type == EventGameCreated || e.Type == EventRobotAdded && len(e.Names) > 0 {
+			names = e.Names
+		}
+	}
+	return names
+}
+
+// handLine describes a hand, with each card numbered as it is played, e.g. "Your hand: 1) Q♠  2) 9♣".
+func handLine(h Hand) string {
+	cards := make([]string, len(h))
+	for i, c := range h {
+		cards[i] = strconv.Itoa(i+1) + ") " + c.colourString()
+	}
+	return translate("Your hand: %v", strings.Join(cards, "  "))
+}
+
+// play carries out a command at the person's table.
+func (c *client) play(seat *Seat, cmd, arg string) {
+	var err error
+	switch cmd {
+	case "hand", "count", "who":
+		var v NNView
+		if v, err = seat.View(); err != nil {
+			if info := seat.Table().Info(); cmd == "who" && !info.Playing {
+				c.send(translate("Waiting for the game to begin: %v", listNames(info.Players)))
+				err = nil
+			}
+			break
+		}
+		switch cmd {
+		case "hand":
+			c.send(handLine(v.Hand))
+		case "count":
+			c.send(translate("Count: %v", v.Count))
+		case "who":
+			c.who(seat, v)
+		}
+	case "play":
+		n, convErr := strconv.Atoi(arg)
+		if convErr != nil {
+			c.send(translate("Enter play and the number of a card in your hand, e.g. play 2."))
+			return
+		}
+		err = seat.Play(n - 1)
+	case "undo":
+		err = seat.Undo()
+	case "chat":
+		err = seat.Chat(arg)
+	case "emote":
+		if arg == "" {
+			c.send(translate("Emotes: %v", strings.Join(EmoteNames(), ", ")))
+			break
+		}
+		err = seat.Emote(arg)
+	case "mute", "unmute":
+		err = seat.Mute(arg, cmd == "mute")
+	case "pause", "unpause":
+		err = seat.Pause(cmd == "pause")
+	case "kick":
+		name, robot := arg, false
+		if i := strings.LastIndexByte(arg, ' '); i >= 0 && strings.EqualFold(arg[i+1:], "robot") {
+			name, robot = strings.TrimSpace(arg[:i]), true
+		}
+		err = seat.Kick(name, robot)
+	case "robot":
+		err = c.seatRobot(seat, arg)
+	case "rules":
+		err = c.changeRules(seat, arg)
+	case "leave":
+		if err = seat.Leave(); err == nil {
+			c.stand()
+			c.send(translate("You leave the table."))
+		}
+	}
+	if err != nil {
+		c.send(translate("Error: %v", err))
+	}
+}
+
+// who lists the players at the table, their lives and whose turn it is.
+func (c *client) who(seat *Seat, v NNView) {
+	names := seatedNames(seat.Table().Events().History())
+	for i, name := range names {
+		line := translate("%s has %d lives left", name, v.Lives[i])
+		if !v.Active[i] {
+			line = translate("%s is out of the game", name)
+		}
+		marker := "  "
+		if i == v.Current {
+			marker = "▶ "
+		}
+		if i == v.Seat {
+			line += " " + translate("(you)")
+		}
+		c.send(marker + line)
+	}
+}
+
+// seatRobot seats or unseats a robot for the host. Once the game has started, the robot comes or goes
+// when the next round is dealt, so the host is told it will.
+func (c *client) seatRobot(seat *Seat, arg string) error {
+	var err error
+	switch strings.ToLower(arg) {
+	case "add":
+		err = seat.AddRobot()
+	case "remove":
+		err = seat.RemoveRobot()
+	default:
+		c.send(translate("Enter robot add or robot remove."))
+		return nil
+	}
+	if err == nil && seat.Table().Info().Playing {
+		c.send(translate("Done. The robots will change when the next round is dealt."))
+	}
+	return err
+}
+
+// changeRules shows the table's rules, or changes one of them for the host, e.g. "lives 5" or "undo on".
+func (c *client) changeRules(seat *Seat, arg string) error {
+	settings := seat.Table().Info().Settings
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		c.send(rulesLine(settings))
+		return nil
+	} else if len(fields) != 2 {
+		c.send(translate("Enter rules and a rule with its value: lives 1-%d, cards 1-%d, max 20-%d or undo on/off.", maxRuleLives, maxRuleCards, maxRuleCount))
+		return nil
+	}
+	rule, value := strings.ToLower(fields[0]), strings.ToLower(fields[1])
+	n, convErr := strconv.Atoi(value)
+	switch {
+	case rule == "lives" && convErr == nil && 1 <= n && n <= maxRuleLives:
+		settings.LivesPerPlayer = n
+	case rule == "cards" && convErr == nil && 1 <= n && n <= maxRuleCards:
+		settings.CardsPerPlayer = n
+	case rule == "max" && convErr == nil && 20 <= n && n <= maxRuleCount:
+		settings.MaxCount = n
+	case rule == "undo" && (value == "on" || value == "off"):
+		settings.Undo = value == "on"
+	default:
+		c.send(translate("Enter rules and a rule with its value: lives 1-%d, cards 1-%d, max 20-%d or undo on/off.", maxRuleLives, maxRuleCards, maxRuleCount))
+		return nil
+	}
+	return seat.SetRules(settings)
+}
+
+// The most lives, cards each and highest count the host may choose for a table's game.
+const (
+	maxRuleLives = 10
+	maxRuleCards = 10
+	maxRuleCount = 999
+)
+
+// rulesLine describes the rules a table's game is played by, e.g. "Rules: lives 3, cards 3, up to 99, no take-backs".
+func rulesLine(settings NNGameSettings) string {
+	undo := translate("no take-backs")
+	if settings.Undo {
+		undo = translate("take-backs allowed")
+	}
+	return translate("Rules: lives %d, cards %d, up to %d, %s", settings.LivesPerPlayer, settings.CardsPerPlayer, settings.MaxCount, undo)
+}
@@ -0,0 +1,300 @@
+package cards
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"shuffle/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testConn is a person connected to a lobby over the table protocol, through a pipe.
+type testConn struct {
+	t     *testing.T
+	conn  net.Conn
+	lines chan string
+}
+
+// connect connects to the lobby, and answers the server's question with a name.
+func connect(ctx context.Context, t *testing.T, lobby *Lobby, name string) *testConn {
+	client, server := net.Pipe()
+	go serveClient(ctx, server, lobby)
+	c := &testConn{t: t, conn: client, lines: make(chan string, clientBuffer)}
+	go func() {
+		defer close(c.lines)
+		lines := bufio.NewScanner(client)
+		for lines.Scan() {
+			c.lines <- lines.Text()
+		}
+	}()
+	c.expect("What's your name?")
+	c.say(name)
+	c.expect("Hello, " + name)
+	return c
+}
+
+// say sends a line to the server.
+func (c *testConn) say(line string) {
+	fmt.Fprintln(c.conn, line)
+}
+
+// expect reads lines from the server until one contains the text, and returns it.
+func (c *testConn) expect(text string) string {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line, ok := <-c.lines:
+			if !ok {
+				c.t.Fatalf("hung up before %q", text)
+			}
+			if strings.Contains(line, text) {
+				return line
+			}
+		case <-timeout:
+			c.t.Fatalf("never received %q", text)
+		}
+	}
+}
+
+// playFirstCards plays the first card in the person's hand on each of their turns, until the table closes.
+// It returns every line received.
+func (c *testConn) playFirstCards() <-chan []string {
+	received := make(chan []string, 1)
+	go func() {
+		var all []string
+		for line := range c.lines {
+			all = append(all, line)
+			if strings.HasPrefix(line, "Your turn!") {
+				c.say("play 1")
+			} else if strings.HasPrefix(line, "The table has closed") {
+				break
+			}
+		}
+		received <- all
+	}()
+	return received
+}
+
+var tokenPattern = regexp.MustCompile(`resume (\w+) to`)
+
+func TestServeLobby(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lobby := NewLobby(LobbyConfig{Seats: 2})
+	defer lobby.Close()
+
+	alice := connect(ctx, t, lobby, "Alice")
+	alice.say("hand")
+	alice.expect("You are not at a table")
+	alice.say("tables")
+	alice.expect("There are no tables")
+	alice.say("new 2")
+	alice.expect("You sit down at table t1")
+	alice.expect("Table t1 is waiting for 1 more player.")
+	alice.say("new")
+	alice.expect("You are already at table t1")
+
+	bob := connect(ctx, t, lobby, "Bob")
+	bob.say("tables")
+	utils.Error(t, bob.expect("t1:"), "t1: 1/2 seats, 3 lives, up to 99 - Alice")
+	bob.say("join t1")
+	bob.expect("You sit down at table t1")
+	bob.expect("The game begins: Alice and Bob")
+	alice.expect("The game begins: Alice and Bob")
+
+	alice.say("chat hi Bob")
+	bob.expect("Alice: hi Bob")
+	alice.say("emote")
+	alice.expect("Emotes: close, gg, luck, nines, oops, shuffle, thanks, wow")
+	alice.say("emote gg")
+	bob.expect("Alice: Good game!")
+	bob.say("mute Alice")
+	bob.expect("Error: only the host can do that")
+	alice.say("who")
+	utils.Error(t, alice.expect("Alice has"), "▶ Alice has 3 lives left (you)")
+	utils.Error(t, alice.expect("Bob has"), "  Bob has 3 lives left")
+	bob.say("play 1")
+	bob.expect("Error: playing out of turn")
+	bob.say("hand")
+	bob.expect("Your hand: 1) ")
+	bob.say("count")
+	bob.expect("Count: ")
+	bob.say("dance")
+	bob.expect(`Unknown command "dance"`)
+
+	alice.say("play 1")
+	alice.expect("Alice plays")
+	alices, bobs := alice.playFirstCards(), bob.playFirstCards()
+	for _, lines := range [][]string{<-alices, <-bobs} {
+		text := strings.Join(lines, "\n")
+		utils.Error(t, strings.Contains(text, "wins the game!"), true, text)
+		utils.Error(t, strings.Contains(text, "The game is over."), true, text)
+	}
+	alice.say("tables")
+	alice.expect("There are no tables")
+	alice.say("quit")
+	alice.expect("Goodbye!")
+}
+
+func TestServeLobbyResume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lobby := NewLobby(LobbyConfig{Seats: 2, Grace: time.Minute})
+	defer lobby.Close()
+
+	alice := connect(ctx, t, lobby, "Alice")
+	alice.say("new 2 private")
+	code := strings.TrimSuffix(strings.TrimPrefix(alice.expect("join code"), "Your private table's join code is "), ".")
+	token := tokenPattern.FindStringSubmatch(alice.expect("You sit down"))[1]
+
+	bob := connect(ctx, t, lobby, "Bob")
+	bob.say("tables")
+	bob.expect("There are no tables")
+	bob.say("join " + strings.ToLower(code))
+	bob.expect("The game begins")
+	bob.say("chat see you")
+	alice.expect("Bob: see you")
+
+	alice.conn.Close()
+	bob.expect("Alice lost their connection")
+	bob.say("chat where did you go?")
+
+	again := connect(ctx, t, lobby, "Alice")
+	again.say("resume not-a-token")
+	again.expect("Error: no seat is held for that session")
+	again.say("resume " + token)
+	again.expect("You sit down at table")
+	again.expect("Alice lost their connection")
+	again.expect("Bob: where did you go?")
+	again.expect("Your hand: 1) ")
+	bob.expect("Alice is back")
+}
+
+func TestServeLobbyResumeElsewhere(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lobby := NewLobby(LobbyConfig{Seats: 2, Grace: time.Minute})
+	defer lobby.Close()
+
+	alice := connect(ctx, t, lobby, "Alice")
+	alice.say("new 2")
+	token := tokenPattern.FindStringSubmatch(alice.expect("You sit down"))[1]
+	bob := connect(ctx, t, lobby, "Bob")
+	bob.say("join t1")
+	bob.expect("The game begins")
+
+	again := connect(ctx, t, lobby, "Alice")
+	again.say("resume " + token)
+	again.expect("You sit down at table t1")
+	again.expect("Your hand: 1) ")
+	alice.expect("Your seat has been resumed from another connection.")
+	alice.conn.Close()
+	time.Sleep(2 * timerTick)
+
+	again.say("hand")
+	again.expect("Your hand: 1) ")
+	lobby.mu.Lock()
+	seat := lobby.sessions[token]
+	lobby.mu.Unlock()
+	utils.Error(t, seat.Connected(), true, "closing the previous connection leaves the seat connected")
+	for _, e := range seat.Table().Events().History() {
+		utils.Error(t, e.Type != EventDisconnected && e.Type != EventReplaced, true, "no robot takes over")
+	}
+}
+
+func TestServeLobbyUndo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lobby := NewLobby(LobbyConfig{Seats: 2})
+	defer lobby.Close()
+
+	alice := connect(ctx, t, lobby, "Alice")
+	alice.say("new 2")
+	alice.expect("You sit down at table t1")
+	alice.say("rules undo on")
+	alice.expect("The host has changed the rules")
+	bob := connect(ctx, t, lobby, "Bob")
+	bob.say("join t1")
+	bob.expect("The game begins")
+	alice.say("play 1")
+	alice.expect("Alice plays")
+	bob.say("undo")
+	bob.expect("Error: only the player who played the latest card can take it back")
+	alice.say("undo")
+	alice.expect("Alice takes back")
+	alice.expect("Your turn!")
+	alice.say("undo")
+	alice.expect("Error: nothing to take back")
+}
+
+func TestServeLobbyLeave(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lobby := NewLobby(LobbyConfig{Seats: 3})
+	defer lobby.Close()
+
+	alice := connect(ctx, t, lobby, "Alice")
+	alice.say("quick")
+	alice.expect("You sit down at table t1")
+	alice.say("who")
+	alice.expect("Waiting for the game to begin: Alice")
+	alice.say("leave")
+	alice.expect("You leave the table.")
+	alice.say("tables")
+	utils.Error(t, alice.expect("t1:"), "t1: 0/3 seats, 3 lives, up to 99")
+
+	bob := connect(ctx, t, lobby, "Bob")
+	bob.say("join t1")
+	bob.expect("You sit down")
+	bob.conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(lobby.Tables()[0].Players) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	utils.Error(t, lobby.Tables()[0].Players, []string(nil), "hanging up before the game gives up the seat")
+}
+
+func TestServeLobbyHost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lobby := NewLobby(LobbyConfig{Seats: 3})
+	defer lobby.Close()
+
+	alice := connect(ctx, t, lobby, "Alice")
+	alice.say("new 3")
+	alice.expect("You sit down at table t1")
+	alice.say("rules lives 1")
+	alice.expect("The host has changed the rules")
+	alice.say("rules lives many")
+	alice.expect("Enter rules and a rule with its value")
+	alice.say("rules")
+	alice.expect("Rules: lives 1, cards 3, up to 99, no take-backs")
+
+	bob := connect(ctx, t, lobby, "Bob")
+	bob.say("join t1")
+	bob.expect("You sit down at table t1")
+	bob.say("kick Alice")
+	bob.expect("Error: only the host can do that")
+	alice.say("kick bob")
+	bob.expect("The host has removed Bob from the table")
+	bob.say("hand")
+	bob.expect("You are not at a table")
+
+	alice.say("robot add")
+	alice.expect("A robot sits down at the table")
+	carol := connect(ctx, t, lobby, "Carol")
+	carol.say("join t1")
+	carol.expect("The game begins: Alice, Carol and Robot 1")
+	alice.say("pause")
+	carol.expect("The host has paused the game")
+	carol.say("play 1")
+	carol.expect("Error: the game is paused")
+	alice.say("unpause")
+	carol.expect("The game resumes")
+	alice.say("robot add")
+	alice.expect("Done. The robots will change when the next round is dealt.")
+}
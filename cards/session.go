@@ -0,0 +1,207 @@
+package cards
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sessionTokenBytes is the number of random bytes in a session token.
+const sessionTokenBytes = 16
+
+// newSessionToken generates a random session token, with which a person can reconnect to their seat.
+func newSessionToken() (string, error) {
+	b := make([]byte, sessionTokenBytes)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", errors.Wrap(err, "cannot generate a session token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Token returns the seat's session token. Whoever holds it can reconnect to the seat after losing their connection,
+// so it should only be given to the person in the seat.
+func (s *Seat) Token() string {
+	return s.token
+}
+
+// connection identifies one of the connections through which a person has sat in a seat.
+type connection struct {
+	gen        int             // counts the times someone has reconnected to the seat
+	superseded <-chan struct{} // closed once someone reconnects to the seat through another connection
+}
+
+// Connection returns the generation of the latest connection to the seat.
+// It begins at 0, and moves on each time someone reconnects to the seat with its session token.
+func (s *Seat) Connection() int {
+	gen := 0
+	s.table.Do(func(mgr *NNGameManager) {
+		gen = s.conn
+	})
+	return gen
+}
+
+// connection returns the latest connection to the seat.
+func (s *Seat) connection() connection {
+	var c connection
+	s.table.Do(func(mgr *NNGameManager) {
+		c = connection{gen: s.conn, superseded: s.superseded}
+	})
+	return c
+}
+
+// Disconnect records that the person in the seat has lost the connection with the given generation.
+// Their seat is held for them for the lobby's grace period, during which they can Reconnect with their session token.
+// If they do not come back in time, a robot takes over their hand, or, if the game has not started, their seat is given up.
+// It does nothing if someone has reconnected to the seat since that connection was made.
+func (s *Seat) Disconnect(gen int) error {
+	return s.table.Do(func(mgr *NNGameManager) {
+		if gen == s.conn {
+			s.disconnect(mgr)
+		}
+	})
+}
+
+// disconnect records that the person in the seat has lost their connection; it must be called on the table's goroutine.
+func (s *Seat) disconnect(mgr *NNGameManager) {
+	if s.replaced || !s.lost.IsZero() {
+		return
+	}
+	s.lost = mgr.now()
+	s.seen = mgr.Events().LastSeq()
+	if mgr.Playing() {
+		mgr.emit(EventDisconnected, s.player.ID(), nil)
+	}
+}
+
+// hangUp gives up the seat before the game begins, or holds it once it has begun,
+// when the connection with the given generation closes.
+// It does nothing if someone has reconnected to the seat since that connection was made.
+func (s *Seat) hangUp(gen int) {
+	left := false
+	s.table.Do(func(mgr *NNGameManager) {
+		if gen != s.conn || s.replaced {
+			return
+		}
+		if left = s.table.unseat(s); !left {
+			s.disconnect(mgr)
+		}
+	})
+	if left {
+		s.table.lobby.endSession(s)
+	}
+}
+
+// Connected returns true unless the person in the seat has lost their connection.
+func (s *Seat) Connected() bool {
+	connected := false
+	s.table.Do(func(mgr *NNGameManager) {
+		connected = s.lost.IsZero() && !s.replaced
+	})
+	return connected
+}
+
+// Reconnect returns the seat held for the person with the session token, along with every Event of the game
+// they missed while they were disconnected. Their private view of the game can then be restored with View.
+// If they are still connected to the seat, it is taken from their previous connection, whose generation is now stale.
+// It returns an error if the token is unknown, or their seat is no longer held for them.
+func (l *Lobby) Reconnect(token string) (*Seat, []Event, error) {
+	s, missed, _, err := l.reconnect(token)
+	return s, missed, err
+}
+
+// reconnect reconnects to the seat held for the session token, as Reconnect does, and returns the new connection.
+func (l *Lobby) reconnect(token string) (*Seat, []Event, connection, error) {
+	l.mu.Lock()
+	s, ok := l.sessions[token]
+	l.mu.Unlock()
+	if !ok {
+		return nil, nil, connection{}, errors.New("no seat is held for that session")
+	}
+	var missed []Event
+	var conn connection
+	var err error
+	doErr := s.table.Do(func(mgr *NNGameManager) {
+		if s.replaced {
+			err = errors.New("no seat is held for that session")
+			return
+		}
+		if !s.lost.IsZero() {
+			missed = mgr.Events().Since(s.seen)
+			s.lost = time.Time{}
+			if mgr.Playing() {
+				mgr.emit(EventReconnected, s.player.ID(), nil)
+			}
+		}
+		s.conn++
+		close(s.superseded)
+		s.superseded = make(chan struct{})
+		conn = connection{gen: s.conn, superseded: s.superseded}
+	})
+	if doErr != nil {
+		return nil, nil, connection{}, doErr
+	} else if err != nil {
+		return nil, nil, connection{}, err
+	}
+	return s, missed, conn, nil
+}
+
+// endSession forgets the session token of a seat that has been given up.
+func (l *Lobby) endSession(s *Seat) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.sessions, s.token)
+}
+
+// unseat gives up a seat before the game has started.
+// It returns false if the game has already started.
+func (t *LobbyTable) unseat(s *Seat) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.playing {
+		return false
+	}
+	for i, seated := range t.seats {
+		if seated == s {
+			t.seats = append(t.seats[:i:i], t.seats[i+1:]...)
+		}
+	}
+	s.replaced = true
+	t.passHost(s)
+	return true
+}
+
+// releaseSeats gives up the seats of everyone who has been disconnected for longer than the grace period.
+// Once the game has started, a robot takes over their hand.
+func (t *LobbyTable) releaseSeats() {
+	t.mu.Lock()
+	seats := append([]*Seat{}, t.seats...)
+	t.mu.Unlock()
+	for _, s := range seats {
+		if s.replaced || s.lost.IsZero() || t.mgr.now().Sub(s.lost) < t.lobby.cfg.Grace {
+			continue
+		}
+		if !t.unseat(s) {
+			t.mgr.takeOver(s.player)
+			t.mu.Lock()
+			s.replaced = true
+			t.passHost(s)
+			t.mu.Unlock()
+		}
+		t.lobby.endSession(s)
+	}
+	t.resumeUnhosted()
+}
+
+// takeOver hands a player's cards to a robot, which plays cautiously for them for the rest of the game.
+func (mgr *NNGameManager) takeOver(p *NNPlayer) {
+	if p.Robot() {
+		return
+	}
+	p.strategy = NewCautiousStrategy()
+	if mgr.Playing() {
+		mgr.emit(EventReplaced, p.ID(), nil)
+	}
+}
@@ -0,0 +1,130 @@
+package cards
+
+import (
+	"shuffle/utils"
+	"testing"
+	"time"
+)
+
+// waitFor waits for an Event of the given type at the table.
+func waitFor(t *testing.T, table *LobbyTable, typ EventType) Event {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, e := range table.Events().History() {
+			if e.Type == typ {
+				return e
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("no %s event", typ)
+	return Event{}
+}
+
+// seatPair seats Alice and Bob at a table of two, and makes it Bob's turn.
+func seatPair(t *testing.T, lobby *Lobby) (alice, bob *Seat) {
+	table, err := lobby.CreateTable(TableOptions{Seats: 2})
+	utils.Fatal(t, err, nil)
+	alice, err = lobby.Join(table.ID(), "Alice")
+	utils.Fatal(t, err, nil)
+	bob, err = lobby.Join(table.ID(), "Bob")
+	utils.Fatal(t, err, nil)
+	table.Do(func(mgr *NNGameManager) {
+		mgr.table.SetCurrSeat(1)
+	})
+	return alice, bob
+}
+
+func TestReconnect(t *testing.T) {
+	cfg := testLobby
+	cfg.Grace = time.Minute
+	lobby := NewLobby(cfg)
+	defer lobby.Close()
+	alice, bob := seatPair(t, lobby)
+	utils.Error(t, alice.Token() != bob.Token(), true, "every seat has its own token")
+
+	utils.Fatal(t, alice.Disconnect(alice.Connection()), nil)
+	utils.Error(t, alice.Connected(), false)
+	utils.Fatal(t, bob.Play(0), nil)
+
+	seat, missed, err := lobby.Reconnect(alice.Token())
+	utils.Fatal(t, err, nil)
+	utils.Error(t, seat, alice)
+	utils.Error(t, seat.Connected(), true)
+	utils.Error(t, missed[0].Type, EventDisconnected)
+	utils.Error(t, missed[0].Seat, 0)
+	utils.Error(t, missed[1].Type, EventCardPlayed, "missed events")
+	utils.Error(t, missed[1].Seat, 1)
+	history := alice.Table().Events().History()
+	utils.Error(t, history[len(history)-1].Type, EventReconnected)
+
+	v, err := seat.View()
+	utils.Fatal(t, err, nil)
+	utils.Error(t, len(v.Hand), NNDefaultSettings.CardsPerPlayer, "private view is restored")
+	stale := alice.Connection()
+	_, missed, err = lobby.Reconnect(alice.Token())
+	utils.Error(t, err, nil, "reconnecting while connected")
+	utils.Error(t, len(missed), 0)
+	utils.Error(t, alice.Connection(), stale+1, "the connection moves on")
+	utils.Fatal(t, alice.Disconnect(stale), nil)
+	utils.Error(t, alice.Connected(), true, "the previous connection cannot disconnect the seat")
+
+	_, _, err = lobby.Reconnect("not a token")
+	utils.Error(t, err != nil, true, "unknown token")
+}
+
+func TestRobotTakesOver(t *testing.T) {
+	cfg := testLobby
+	cfg.Grace = time.Minute
+	lobby := NewLobby(cfg)
+	defer lobby.Close()
+	alice, bob := seatPair(t, lobby)
+	clock := &fakeClock{now: time.Date(2021, time.December, 25, 9, 0, 0, 0, time.UTC)}
+	alice.Table().Do(func(mgr *NNGameManager) { mgr.SetClock(clock) })
+
+	utils.Fatal(t, alice.Disconnect(0), nil)
+	time.Sleep(2 * timerTick)
+	utils.Error(t, alice.Connected(), false, "the seat is held during the grace period")
+	clock.Advance(cfg.Grace)
+	replaced := waitFor(t, alice.Table(), EventReplaced)
+	utils.Error(t, replaced.Seat, 0)
+	_, _, err := lobby.Reconnect(alice.Token())
+	utils.Error(t, err != nil, true, "the seat is no longer held")
+
+	playOut(t, bob)
+	played := 0
+	for _, e := range alice.Table().Events().Since(replaced.Seq) {
+		if e.Type == EventCardPlayed && e.Seat == 0 {
+			played++
+		}
+	}
+	utils.Error(t, played > 0, true, "the robot plays Alice's hand")
+}
+
+func TestDisconnectBeforeGame(t *testing.T) {
+	cfg := testLobby
+	cfg.Grace = time.Minute
+	lobby := NewLobby(cfg)
+	defer lobby.Close()
+	table, err := lobby.CreateTable(TableOptions{Seats: 3})
+	utils.Fatal(t, err, nil)
+	clock := &fakeClock{now: time.Date(2021, time.December, 25, 9, 0, 0, 0, time.UTC)}
+	table.Do(func(mgr *NNGameManager) { mgr.SetClock(clock) })
+	alice, err := lobby.Join(table.ID(), "Alice")
+	utils.Fatal(t, err, nil)
+	_, err = lobby.Join(table.ID(), "Bob")
+	utils.Fatal(t, err, nil)
+
+	utils.Fatal(t, alice.Disconnect(0), nil)
+	time.Sleep(2 * timerTick)
+	utils.Error(t, len(table.Info().Players), 2, "the seat is held during the grace period")
+	clock.Advance(cfg.Grace)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(table.Info().Players) > 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	utils.Error(t, table.Info().Players, []string{"Bob"}, "the seat is given up")
+	_, _, err = lobby.Reconnect(alice.Token())
+	utils.Error(t, err != nil, true, "the seat is no longer held")
+	utils.Error(t, len(table.Events().History()), 0, "no events before the game")
+}
@@ -0,0 +1,394 @@
+package cards
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+// maxSimulatedTurns caps the length of a simulated game, in case the rules allow endless games.
+const maxSimulatedTurns = 100000
+
+// SimConfig describes a batch of simulated games of 99 played entirely by robots.
+type SimConfig struct {
+	Games      int
+	Workers    int
+	Seed       int64
+	Settings   *NNGameSettings
+	Strategies []string // the name of the strategy played by each seat, in seating order
+}
+
+// SimReport summarizes a batch of simulated games of 99.
+type SimReport struct {
+	Games            int            `json:"games"`
+	Unfinished       int            `json:"unfinished"`
+	Turns            int            `json:"turns"`
+	MinTurns         int            `json:"min_turns"`
+	MaxTurns         int            `json:"max_turns"`
+	Rounds           int            `json:"rounds"`
+	MinRounds        int            `json:"min_rounds"`
+	MaxRounds        int            `json:"max_rounds"`
+	RoundsBySeat     []int          `json:"rounds_by_seat"` // the rounds each seat was dealt into
+	BustsBySeat      []int          `json:"busts_by_seat"`
+	RoundsByStrategy map[string]int `json:"rounds_by_strategy"`
+	BustsByStrategy  map[string]int `json:"busts_by_strategy"`
+	WildPlays        map[Rank]int   `json:"wild_plays"`
+	WildBusts        map[Rank]int   `json:"wild_busts"`
+	Strategies       []string       `json:"strategies"`
+	WinsByStrategy   map[string]int `json:"wins_by_strategy"`
+}
+
+// newSimReport creates an empty report for games between the given strategies.
+func newSimReport(strategies []string) *SimReport {
+	return &SimReport{
+		RoundsBySeat:     make([]int, len(strategies)),
+		BustsBySeat:      make([]int, len(strategies)),
+		RoundsByStrategy: make(map[string]int),
+		BustsByStrategy:  make(map[string]int),
+		WildPlays:        make(map[Rank]int),
+		WildBusts:        make(map[Rank]int),
+		Strategies:       append([]string{}, strategies...),
+		WinsByStrategy:   make(map[string]int),
+	}
+}
+
+// Simulate plays a batch of games of 99 between robots and reports on how they played out.
+// Games are split evenly between workers, which run in parallel. Worker w seeds its shuffles
+// and strategies with Seed+w, so a batch can be reproduced with the same seed and number of workers.
+// The strategies move round the table one seat each game, so that every strategy plays from every seat.
+func Simulate(ctx context.Context, cfg SimConfig) (*SimReport, error) {
+	if len(cfg.Strategies) < 2 {
+		return nil, errors.New("at least two players are needed")
+	}
+	for _, name := range cfg.Strategies {
+		if err := checkNNStrategy(name); err != nil {
+			return nil, err
+		}
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	reports := make([]*SimReport, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			reports[w], errs[w] = simulateWorker(ctx, cfg, w, workers)
+		}(w)
+	}
+	wg.Wait()
+
+	total := newSimReport(cfg.Strategies)
+	for w, r := range reports {
+		if errs[w] != nil {
+			return nil, errs[w]
+		}
+		total.merge(r)
+	}
+	return total, nil
+}
+
+// simulateWorker plays every game in the batch assigned to worker w of n.
+func simulateWorker(ctx context.Context, cfg SimConfig, w, n int) (*SimReport, error) {
+	seed := cfg.Seed + int64(w)
+	rng := NewRngAt(seed)
+	strategies := make([]NNStrategy, len(cfg.Strategies))
+	defer func() {
+		for _, s := range strategies {
+			if s != nil {
+				closeStrategy(s)
+			}
+		}
+	}()
+	for i, name := range cfg.Strategies {
+		s, err := NewNNStrategy(name, seed+int64(i)*int64(n))
+		if err != nil {
+			return nil, err
+		}
+		strategies[i] = s
+	}
+
+	report := newSimReport(cfg.Strategies)
+	for g := w; g < cfg.Games; g += n {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := simulateGame(ctx, cfg.Settings, strategies, g%len(strategies), rng, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// simulateGame plays a single game of 99 between robots, and adds it to the report.
+// The first seat is taken by the strategy at position offset, and the rest follow in order.
+func simulateGame(ctx context.Context, settings *NNGameSettings, strategies []NNStrategy, offset int, rng Randomizer, report *SimReport) error {
+	seated := append(append([]NNStrategy{}, strategies[offset:]...), strategies[:offset]...)
+	game, err := playRobotGame(ctx, settings, seated, rng)
+	if err != nil {
+		return err
+	}
+	game.finish(report, offset)
+	return nil
+}
+
+// playRobotGame plays a single game of 99 between robots, seated in the order of their strategies,
+// and returns a record of how it played out.
+func playRobotGame(ctx context.Context, settings *NNGameSettings, strategies []NNStrategy, rng Randomizer) (*simRecorder, error) {
+	players := make([]*NNPlayer, len(strategies))
+	for i, s := range strategies {
+		players[i] = NewNNRobot(fmt.Sprintf("Robot %d", i+1), s)
+	}
+
+	mgr := new(NNGameManager)
+	mgr.SetRandomizer(rng)
+	mgr.events = NewEventStream()
+	game := newSimRecorder(mgr)
+	mgr.events.Subscribe(game.record)
+	mgr.StartGame(players, 0, settings)
+
+	for mgr.Playing() && game.turns < maxSimulatedTurns {
+		if err := mgr.PlayTurn(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return game, nil
+}
+
+// simRecorder tallies the Events of a single simulated game.
+type simRecorder struct {
+	mgr       *NNGameManager
+	turns     int
+	rounds    int
+	played    []int // the rounds each seat was dealt into
+	busts     []int
+	wildPlays map[Rank]int
+	wildBusts map[Rank]int
+	previous  Rank // the rank of the card played before the latest one this round
+	latest    Rank
+	out       []int // the seats eliminated, in the order they were eliminated
+	winner    int
+}
+
+// newSimRecorder creates a recorder for the game run by the manager.
+func newSimRecorder(mgr *NNGameManager) *simRecorder {
+	return &simRecorder{
+		mgr:       mgr,
+		wildPlays: make(map[Rank]int),
+		wildBusts: make(map[Rank]int),
+		winner:    NoSeat,
+	}
+}
+
+// record tallies a single Event.
+// A bust is blamed on a wild card if the card played just before the busting card was wild.
+func (r *simRecorder) record(e Event) {
+	switch e.Type {
+	case EventDealt:
+		r.rounds++
+		r.previous, r.latest = "", ""
+		for _, p := range r.mgr.table.Remaining() {
+			for len(r.played) <= p.ID() {
+				r.played = append(r.played, 0)
+			}
+			r.played[p.ID()]++
+		}
+	case EventCardPlayed:
+		r.turns++
+		rank := e.Cards[0].rank
+		r.previous, r.latest = r.latest, rank
+		if r.mgr.settings.IsWild(rank) {
+			r.wildPlays[rank]++
+		}
+	case EventBusted:
+		for len(r.busts) <= e.Seat {
+			r.busts = append(r.busts, 0)
+		}
+		r.busts[e.Seat]++
+		if r.mgr.settings.IsWild(r.previous) {
+			r.wildBusts[r.previous]++
+		}
+	case EventEliminated:
+		r.out = append(r.out, e.Seat)
+	case EventWon:
+		r.winner = e.Seat
+	}
+}
+
+// finish adds the tallies of a completed game to the report.
+// The strategy at position offset in the report sat in the first seat.
+func (r *simRecorder) finish(report *SimReport, offset int) {
+	strategy := func(seat int) string {
+		return report.Strategies[(seat+offset)%len(report.Strategies)]
+	}
+	if r.winner == NoSeat {
+		report.Unfinished++
+	} else {
+		report.WinsByStrategy[strategy(r.winner)]++
+	}
+	for seat, rounds := range r.played {
+		report.RoundsByStrategy[strategy(seat)] += rounds
+	}
+	for seat, busts := range r.busts {
+		report.BustsByStrategy[strategy(seat)] += busts
+	}
+	one := &SimReport{
+		Games:        1,
+		Turns:        r.turns,
+		MinTurns:     r.turns,
+		MaxTurns:     r.turns,
+		Rounds:       r.rounds,
+		MinRounds:    r.rounds,
+		MaxRounds:    r.rounds,
+		RoundsBySeat: r.played,
+		BustsBySeat:  r.busts,
+		WildPlays:    r.wildPlays,
+		WildBusts:    r.wildBusts,
+	}
+	report.merge(one)
+}
+
+// merge adds the tallies of another report to this one.
+func (r *SimReport) merge(o *SimReport) {
+	if o.Games == 0 {
+		return
+	}
+	if r.Games == 0 || o.MinTurns < r.MinTurns {
+		r.MinTurns = o.MinTurns
+	}
+	if r.Games == 0 || o.MinRounds < r.MinRounds {
+		r.MinRounds = o.MinRounds
+	}
+	if o.MaxTurns > r.MaxTurns {
+		r.MaxTurns = o.MaxTurns
+	}
+	if o.MaxRounds > r.MaxRounds {
+		r.MaxRounds = o.MaxRounds
+	}
+	r.Games += o.Games
+	r.Unfinished += o.Unfinished
+	r.Turns += o.Turns
+	r.Rounds += o.Rounds
+	for seat, rounds := range o.RoundsBySeat {
+		r.RoundsBySeat[seat] += rounds
+	}
+	for seat, busts := range o.BustsBySeat {
+		r.BustsBySeat[seat] += busts
+	}
+	for name, rounds := range o.RoundsByStrategy {
+		r.RoundsByStrategy[name] += rounds
+	}
+	for name, busts := range o.BustsByStrategy {
+		r.BustsByStrategy[name] += busts
+	}
+	for rank, n := range o.WildPlays {
+		r.WildPlays[rank] += n
+	}
+	for rank, n := range o.WildBusts {
+		r.WildBusts[rank] += n
+	}
+	for name, n := range o.WinsByStrategy {
+		r.WinsByStrategy[name] += n
+	}
+}
+
+// ratio divides two counts, returning 0 if the denominator is 0.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// simRow is a single statistic in a report, as written to a table or CSV file.
+type simRow struct {
+	section string
+	key     string
+	value   string
+}
+
+// rows flattens the report into statistics, in a stable order.
+func (r *SimReport) rows() []simRow {
+	f := func(x float64) string { return strconv.FormatFloat(x, 'f', 3, 64) }
+	rows := []simRow{
+		{"games", "played", strconv.Itoa(r.Games)},
+		{"games", "unfinished", strconv.Itoa(r.Unfinished)},
+		{"turns", "mean", f(ratio(r.Turns, r.Games))},
+		{"turns", "min", strconv.Itoa(r.MinTurns)},
+		{"turns", "max", strconv.Itoa(r.MaxTurns)},
+		{"rounds", "mean", f(ratio(r.Rounds, r.Games))},
+		{"rounds", "min", strconv.Itoa(r.MinRounds)},
+		{"rounds", "max", strconv.Itoa(r.MaxRounds)},
+	}
+	for seat, busts := range r.BustsBySeat {
+		rows = append(rows, simRow{"bust rate by seat", fmt.Sprintf("p%d", seat), f(ratio(busts, r.RoundsBySeat[seat]))})
+	}
+	var wilds []string
+	for rank := range r.WildPlays {
+		wilds = append(wilds, string(rank))
+	}
+	sort.Strings(wilds)
+	for _, rank := range wilds {
+		rows = append(rows, simRow{"busts per wild card played", rank, f(ratio(r.WildBusts[Rank(rank)], r.WildPlays[Rank(rank)]))})
+	}
+	seats := make(map[string]int)
+	for _, name := range r.Strategies {
+		seats[name]++
+	}
+	var names []string
+	for name := range seats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rows = append(rows, simRow{"bust rate by strategy", name, f(ratio(r.BustsByStrategy[name], r.RoundsByStrategy[name]))})
+	}
+	for _, name := range names {
+		rows = append(rows, simRow{"win rate by strategy", name, f(ratio(r.WinsByStrategy[name], r.Games*seats[name]))})
+	}
+	return rows
+}
+
+// WriteText writes the report as a human-readable table.
+func (r *SimReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	section := ""
+	for _, row := range r.rows() {
+		if row.section != section {
+			section = row.section
+			fmt.Fprintf(tw, "%s\t\t\n", section)
+		}
+		fmt.Fprintf(tw, "\t%s\t%s\n", row.key, row.value)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the report as CSV, with one statistic per row.
+func (r *SimReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"section", "key", "value"})
+	for _, row := range r.rows() {
+		cw.Write([]string{row.section, row.key, row.value})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the report as JSON.
+func (r *SimReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
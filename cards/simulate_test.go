@@ -0,0 +1,86 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"shuffle/utils"
+	"strings"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	cfg := SimConfig{
+		Games:      40,
+		Workers:    3,
+		Seed:       2021,
+		Strategies: []string{"cautious", "aggressive", "random", "random"},
+	}
+	report, err := Simulate(context.Background(), cfg)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, report.Games, 40, "games played")
+
+	wins := 0
+	for _, n := range report.WinsByStrategy {
+		wins += n
+	}
+	utils.Error(t, wins, report.Games-report.Unfinished, "games won")
+
+	busts := 0
+	for _, n := range report.BustsBySeat {
+		busts += n
+	}
+	utils.Error(t, busts, report.Rounds, "busts across all seats")
+	busts = 0
+	for _, n := range report.BustsByStrategy {
+		busts += n
+	}
+	utils.Error(t, busts, report.Rounds, "busts across all strategies")
+	utils.Error(t, report.MinRounds >= 1+2*NNDefaultSettings.LivesPerPlayer, true, "rounds needed to eliminate three players")
+
+	again, err := Simulate(context.Background(), cfg)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, again, report, "for a repeated simulation")
+}
+
+func TestSimulateRotatesSeats(t *testing.T) {
+	report := newSimReport([]string{"cautious", "aggressive", "random"})
+	game := newSimRecorder(nil)
+	game.played = []int{4, 4, 3}
+	game.busts = []int{1, 2, 3}
+	game.winner = 0
+	game.finish(report, 1)
+	utils.Error(t, report.WinsByStrategy, map[string]int{"aggressive": 1}, "the strategy in the first seat won")
+	utils.Error(t, report.BustsByStrategy, map[string]int{"aggressive": 1, "random": 2, "cautious": 3})
+	utils.Error(t, report.BustsBySeat, []int{1, 2, 3})
+	utils.Error(t, report.RoundsByStrategy, map[string]int{"aggressive": 4, "random": 4, "cautious": 3})
+	utils.Error(t, report.rows()[10], simRow{"bust rate by seat", "p2", "1.000"}, "busts are divided by the rounds each seat was dealt into")
+}
+
+func TestSimulateInvalid(t *testing.T) {
+	_, err := Simulate(context.Background(), SimConfig{Games: 1, Strategies: []string{"cautious"}})
+	utils.Error(t, err != nil, true, "error for a single player")
+
+	_, err = Simulate(context.Background(), SimConfig{Games: 1, Strategies: []string{"cautious", "psychic"}})
+	utils.Error(t, err != nil, true, "error for an unknown strategy")
+
+	_, err = Simulate(context.Background(), SimConfig{Games: 1, Strategies: []string{"cautious", BotPrefix}})
+	utils.Error(t, err != nil, true, "error for a bot with no command")
+}
+
+func TestSimReportFormats(t *testing.T) {
+	report, err := Simulate(context.Background(), SimConfig{Games: 5, Workers: 2, Seed: 1, Strategies: []string{"cautious", "random"}})
+	utils.Fatal(t, err, nil)
+
+	var text, csv, js bytes.Buffer
+	utils.Fatal(t, report.WriteText(&text), nil)
+	utils.Error(t, strings.Contains(text.String(), "win rate by strategy"), true, "text contains win rates")
+
+	utils.Fatal(t, report.WriteCSV(&csv), nil)
+	utils.Error(t, strings.HasPrefix(csv.String(), "section,key,value\n"), true, "csv has a header")
+
+	utils.Fatal(t, report.WriteJSON(&js), nil)
+	var decoded SimReport
+	utils.Fatal(t, json.Unmarshal(js.Bytes(), &decoded), nil)
+	utils.Error(t, decoded.Games, 5, "games in decoded json")
+}
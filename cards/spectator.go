@@ -0,0 +1,220 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// spectatorBuffer is how many Events a spectator may fall behind by before they are disconnected,
+// so that a slow spectator can never hold up the game.
+const spectatorBuffer = 1024
+
+// SpectatorConfig describes what a spectator of a game of 99 is shown.
+type SpectatorConfig struct {
+	Commentary bool          // whether every hand and pile is revealed as well, like revealTable
+	Delay      time.Duration // how long hands and piles are held back, so spectators can't tell players in time to matter
+}
+
+// Spectator watches a game of 99 without playing, writing what happens to an io.Writer as lines of text.
+// Spectators see the public state of the game live: every card played and the count, reversals, busts,
+// lives lost and eliminations. Commentators also see every hand, the draw pile and the discard pile
+// after each card is drawn, but only once the configured delay has passed.
+type Spectator struct {
+	mgr         *NNGameManager
+	w           io.Writer
+	cfg         SpectatorConfig
+	mu          sync.Mutex // guards writes to w
+	names       []string
+	events      chan Event
+	reveals     chan spectatorReveal
+	pending     sync.WaitGroup // reveals that have been taken but not yet written
+	done        chan struct{}
+	once        sync.Once
+	unsubscribe func()
+}
+
+// spectatorReveal is every card on the table, as it was at a moment in the game.
+type spectatorReveal struct {
+	at    time.Time
+	table string
+}
+
+// Spectate attaches a spectator to the game run by the manager, writing to w until the game ends,
+// the spectator falls too far behind, a write fails or the spectator is closed.
+// Spectators who attach part way through a game are first shown everything that has happened so far.
+// It is safe to call while the game is being played on another goroutine.
+func Spectate(mgr *NNGameManager, w io.Writer, cfg SpectatorConfig) *Spectator {
+	s := &Spectator{
+		mgr:     mgr,
+		w:       w,
+		cfg:     cfg,
+		events:  make(chan Event, spectatorBuffer),
+		reveals: make(chan spectatorReveal, spectatorBuffer),
+		done:    make(chan struct{}),
+	}
+	s.unsubscribe = mgr.Events().Subscribe(s.record)
+	history := mgr.Events().History()
+	go s.watch(history)
+	if cfg.Commentary {
+		go s.comment()
+	}
+	return s
+}
+
+// Done returns a channel that is closed once the spectator has stopped watching.
+func (s *Spectator) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close stops the spectator watching.
+func (s *Spectator) Close() error {
+	s.once.Do(func() {
+		s.unsubscribe()
+		close(s.done)
+	})
+	return nil
+}
+
+// record passes an Event on to the spectator, on the goroutine playing the game.
+// Commentators are also given the whole table whenever a player's hand changes,
+// which can only be seen safely from this goroutine.
+func (s *Spectator) record(e Event) {
+	if s.cfg.Commentary {
+		switch e.Type {
+		case EventDealt, EventDrew, EventUndone, EventGameEnded:
+			var b bytes.Buffer
+			s.mgr.writeTable(&b)
+			s.pending.Add(1)
+			select {
+			case s.reveals <- spectatorReveal{at: e.Time, table: b.String()}:
+			default:
+				s.pending.Done()
+				s.Close()
+				return
+			}
+		}
+	}
+	select {
+	case s.events <- e:
+	default:
+		s.Close()
+	}
+}
+
+// watch writes the Events of the game so far, then every Event as it happens.
+// Once the game is over, it waits for the final reveals before it stops.
+func (s *Spectator) watch(history []Event) {
+	last := 0
+	for _, e := range history {
+		last = e.Seq
+		if s.show(e) {
+			return
+		}
+	}
+	for {
+		select {
+		case e := <-s.events:
+			if e.Seq > last && s.show(e) {
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// show writes a single Event. It returns true if the spectator has stopped watching.
+func (s *Spectator) show(e Event) bool {
+	switch e.Type {
+	case EventGameCreated:
+		s.names = e.Names
+		s.write(sentence(translate("Watching %v play 99", listNames(s.names))) + "\n")
+	case EventGameEnded:
+		s.write(sentence(translate("The game is over")) + "\n")
+		go func() {
+			s.pending.Wait()
+			s.Close()
+		}()
+		return true
+	default:
+		if e.Type == EventRobotAdded && len(e.Names) > 0 {
+			s.names = e.Names
+		}
+		if line, ok := describeNNEvent(s.names, e); ok {
+			s.write(line + "\n")
+		}
+	}
+	return s.stopped()
+}
+
+// comment writes each reveal once the delay has passed since it was taken.
+func (s *Spectator) comment() {
+	for {
+		select {
+		case r := <-s.reveals:
+			timer := time.NewTimer(time.Until(r.at.Add(s.cfg.Delay)))
+			select {
+			case <-timer.C:
+				s.write(translate("Commentary, %v behind:", s.cfg.Delay) + "\n" + r.table)
+			case <-s.done:
+				timer.Stop()
+			}
+			s.pending.Done()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// write writes text to the spectator, and stops them watching if it fails.
+func (s *Spectator) write(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped() {
+		return
+	}
+	if _, err := io.WriteString(s.w, text); err != nil {
+		s.Close()
+	}
+}
+
+// stopped returns true if the spectator has stopped watching.
+func (s *Spectator) stopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// ServeSpectators lets anyone who connects to the listener watch the game run by the manager,
+// until the context is cancelled. Each connection is closed when its spectator stops watching.
+func ServeSpectators(ctx context.Context, l net.Listener, mgr *NNGameManager, cfg SpectatorConfig) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		s := Spectate(mgr, conn, cfg)
+		go func() {
+			select {
+			case <-s.Done():
+			case <-ctx.Done():
+				s.Close()
+			}
+			conn.Close()
+		}()
+	}
+}
@@ -0,0 +1,109 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"shuffle/utils"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer that spectators can write to while a test reads it.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+// playRobots plays the game between robots to the end.
+func playRobots(t *testing.T, mgr *NNGameManager) {
+	for mgr.Playing() {
+		utils.Fatal(t, mgr.PlayTurn(context.Background()), nil)
+	}
+}
+
+// waitDone waits for a spectator to stop watching.
+func waitDone(t *testing.T, s *Spectator) {
+	select {
+	case <-s.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("spectator never stopped watching")
+	}
+}
+
+func TestSpectator(t *testing.T) {
+	mgr := new(NNGameManager)
+	mgr.SetEvents(NewEventStream())
+	var live, commentary syncBuffer
+	watcher := Spectate(mgr, &live, SpectatorConfig{})
+	commentator := Spectate(mgr, &commentary, SpectatorConfig{Commentary: true, Delay: 200 * time.Millisecond})
+
+	mgr.StartGame(nil, 2, NNDefaultSettings)
+	for !strings.Contains(commentary.String(), "Watching Robot 1 and Robot 2 play 99.\n") {
+		time.Sleep(time.Millisecond)
+	}
+	utils.Error(t, strings.Contains(commentary.String(), "Commentary"), false, "hands are held back")
+
+	playRobots(t, mgr)
+	waitDone(t, watcher)
+	waitDone(t, commentator)
+	utils.Error(t, strings.HasSuffix(live.String(), "The game is over.\n"), true, live.String())
+	utils.Error(t, strings.Contains(live.String(), "wins the game!"), true, "winner is announced")
+	utils.Error(t, strings.Contains(live.String(), "Commentary"), false, "spectators never see hands")
+	utils.Error(t, strings.Contains(commentary.String(), "Commentary, 200ms behind:\nCount: "), true, "hands are revealed after the delay")
+
+	var late syncBuffer
+	waitDone(t, Spectate(mgr, &late, SpectatorConfig{}))
+	utils.Error(t, late.String(), live.String(), "late spectators are shown the game so far")
+}
+
+func TestSpectatorClose(t *testing.T) {
+	mgr := new(NNGameManager)
+	mgr.SetEvents(NewEventStream())
+	var b syncBuffer
+	s := Spectate(mgr, &b, SpectatorConfig{Commentary: true, Delay: time.Hour})
+	mgr.StartGame(nil, 2, NNDefaultSettings)
+	s.Close()
+	waitDone(t, s)
+	playRobots(t, mgr)
+	utils.Error(t, strings.Contains(b.String(), "wins the game!"), false, "closed spectators stop watching")
+}
+
+func TestServeSpectators(t *testing.T) {
+	mgr := new(NNGameManager)
+	mgr.SetEvents(NewEventStream())
+	mgr.StartGame(nil, 3, NNDefaultSettings)
+	playRobots(t, mgr)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	utils.Fatal(t, err, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error)
+	go func() { served <- ServeSpectators(ctx, l, mgr, SpectatorConfig{}) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	utils.Fatal(t, err, nil)
+	text, err := ioutil.ReadAll(conn)
+	conn.Close()
+	utils.Error(t, err, nil)
+	utils.Error(t, strings.HasPrefix(string(text), "Watching Robot 1, Robot 2 and Robot 3 play 99.\n"), true, string(text))
+	utils.Error(t, strings.HasSuffix(string(text), "The game is over.\n"), true, "connection is closed once the game is over")
+
+	cancel()
+	utils.Error(t, <-served, nil)
+}
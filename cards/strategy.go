@@ -0,0 +1,163 @@
+package cards
+
+import (
+	"context"
+	"io"
+	mrand "math/rand"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NNStrategy is an interface for the decision making of robot 99 players.
+// Given a player's view of the game, it chooses the index of a card in their hand to play.
+// Strategies should return promptly once the context is done.
+type NNStrategy interface {
+	Name() string
+	Choose(ctx context.Context, v NNView) (int, error)
+}
+
+// nnStrategies holds a constructor for each built-in strategy, by name.
+// Constructors receive a seed for any randomness the strategy uses.
+var nnStrategies = map[string]func(seed int64) NNStrategy{
+	"random":     func(seed int64) NNStrategy { return NewRandomStrategy(seed) },
+	"cautious":   func(seed int64) NNStrategy { return NewCautiousStrategy() },
+	"aggressive": func(seed int64) NNStrategy { return NewAggressiveStrategy() },
+	"expert":     func(seed int64) NNStrategy { return NewExpertStrategy(ExpertDefaults, seed) },
+}
+
+// NewNNStrategy creates the built-in strategy with the given name.
+// The seed is used by strategies that make random choices.
+// Names starting with BotPrefix start an external bot, which must be closed once the game is over.
+func NewNNStrategy(name string, seed int64) (NNStrategy, error) {
+	if strings.HasPrefix(name, BotPrefix) {
+		return newBotFromName(name)
+	}
+	if f, ok := nnStrategies[name]; ok {
+		return f(seed), nil
+	}
+	return nil, errors.Errorf("unknown strategy %q", name)
+}
+
+// checkNNStrategy returns an error unless NewNNStrategy can create a strategy with the given name.
+// Bots are not launched to check them.
+func checkNNStrategy(name string) error {
+	if strings.HasPrefix(name, BotPrefix) {
+		if len(strings.Fields(strings.TrimPrefix(name, BotPrefix))) == 0 {
+			return errors.New("no bot command given")
+		}
+		return nil
+	}
+	if _, ok := nnStrategies[name]; !ok {
+		return errors.Errorf("unknown strategy %q", name)
+	}
+	return nil
+}
+
+// NNStrategyNames returns the names of the built-in strategies, in alphabetical order.
+func NNStrategyNames() []string {
+	names := make([]string, 0, len(nnStrategies))
+	for name := range nnStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// safeCards returns the indices of the cards in the hand that would not bust the count.
+func safeCards(v NNView) []int {
+	var safe []int
+	for i, c := range v.Hand {
+		if v.Safe(c) {
+			safe = append(safe, i)
+		}
+	}
+	return safe
+}
+
+// randomStrategy plays a random card that does not bust, if it has one.
+type randomStrategy struct {
+	r *mrand.Rand
+}
+
+// NewRandomStrategy creates a strategy that plays a random card that does not bust, if it has one.
+func NewRandomStrategy(seed int64) NNStrategy {
+	return &randomStrategy{r: mrand.New(mrand.NewSource(seed))}
+}
+
+// Name returns the name of the strategy.
+func (s *randomStrategy) Name() string {
+	return "random"
+}
+
+// Choose chooses a random card that does not bust, or any card if every card busts.
+func (s *randomStrategy) Choose(ctx context.Context, v NNView) (int, error) {
+	if len(v.Hand) == 0 {
+		return 0, errors.New("no cards to choose from")
+	}
+	if safe := safeCards(v); len(safe) > 0 {
+		return safe[s.r.Intn(len(safe))], nil
+	}
+	return s.r.Intn(len(v.Hand)), nil
+}
+
+// rankedStrategy plays the card that does not bust with the best score, according to a scoring function.
+// Ties are broken in favour of the card that appears first in the hand.
+type rankedStrategy struct {
+	name  string
+	score func(v NNView, c Card, count int) int
+}
+
+// NewCautiousStrategy creates a strategy that keeps the count as low as it can,
+// holding on to wild cards until it has no other safe card to play.
+func NewCautiousStrategy() NNStrategy {
+	return &rankedStrategy{
+		name: "cautious",
+		score: func(v NNView, c Card, count int) int {
+			score := -count
+			if v.Settings.IsWild(c.rank) {
+				score -= 2 * v.Settings.MaxCount
+			}
+			return score
+		},
+	}
+}
+
+// NewAggressiveStrategy creates a strategy that pushes the count as high as it can without busting,
+// to leave the next player with as little room as possible.
+func NewAggressiveStrategy() NNStrategy {
+	return &rankedStrategy{
+		name: "aggressive",
+		score: func(v NNView, c Card, count int) int {
+			return count
+		},
+	}
+}
+
+// Name returns the name of the strategy.
+func (s *rankedStrategy) Name() string {
+	return s.name
+}
+
+// Choose chooses the best scoring card that does not bust, or the first card if every card busts.
+func (s *rankedStrategy) Choose(ctx context.Context, v NNView) (int, error) {
+	if len(v.Hand) == 0 {
+		return 0, errors.New("no cards to choose from")
+	}
+	best, bestScore := 0, 0
+	for n, i := range safeCards(v) {
+		count, _ := v.Outcome(v.Hand[i])
+		if score := s.score(v, v.Hand[i], count); n == 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, nil
+}
+
+// closeStrategy releases any process or connection held by the strategy, such as an external bot.
+func closeStrategy(s NNStrategy) {
+	if c, ok := s.(io.Closer); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,44 @@
+package cards
+
+import (
+	"context"
+	"shuffle/utils"
+	"testing"
+)
+
+type StrategyResult struct {
+	strategy string
+	count    int
+	hand     Hand
+	want     Card
+}
+
+func TestStrategies(t *testing.T) {
+	hand := Hand{NewCard(Seven, Hearts), NewCard(Nine, Clubs), NewCard(Two, Spades), NewCard(King, Diamonds)}
+	tests := map[string]StrategyResult{
+		"cautious keeps count low":       {"cautious", 50, hand, NewCard(Two, Spades)},
+		"cautious saves wild cards":      {"cautious", 50, Hand{NewCard(King, Diamonds), NewCard(Six, Clubs)}, NewCard(Six, Clubs)},
+		"cautious plays wild when stuck": {"cautious", 95, Hand{NewCard(Seven, Hearts), NewCard(Nine, Clubs), NewCard(King, Diamonds)}, NewCard(King, Diamonds)},
+		"aggressive pushes count":        {"aggressive", 50, hand, NewCard(Nine, Clubs)},
+		"aggressive avoids busting":      {"aggressive", 95, Hand{NewCard(Seven, Hearts), NewCard(Two, Spades)}, NewCard(Two, Spades)},
+		"random avoids busting":          {"random", 95, Hand{NewCard(Seven, Hearts), NewCard(Two, Spades)}, NewCard(Two, Spades)},
+		"every card busts":               {"cautious", 99, Hand{NewCard(Seven, Hearts), NewCard(Two, Spades)}, NewCard(Seven, Hearts)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewNNStrategy(test.strategy, 2021)
+			utils.Fatal(t, err, nil)
+			v := NNView{Hand: test.hand, Count: test.count, Settings: *NNDefaultSettings}
+			i, err := s.Choose(context.Background(), v)
+			utils.Fatal(t, err, nil)
+			utils.Error(t, test.hand[i], test.want)
+		})
+	}
+}
+
+func TestNNStrategyNames(t *testing.T) {
+	utils.Error(t, NNStrategyNames(), []string{"aggressive", "cautious", "expert", "random"})
+	_, err := NewNNStrategy("psychic", 0)
+	utils.Error(t, err != nil, true, "error for an unknown strategy")
+}
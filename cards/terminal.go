@@ -0,0 +1,73 @@
+package cards
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+)
+
+// Terminal escape sequences used by the full-screen interface.
+const (
+	escAltScreen   = "\x1b[?1049h"
+	escMainScreen  = "\x1b[?1049l"
+	escHideCursor  = "\x1b[?25l"
+	escShowCursor  = "\x1b[?25h"
+	escHome        = "\x1b[H"
+	escClearScreen = "\x1b[2J"
+	escClearLine   = "\x1b[K"
+	escClearBelow  = "\x1b[J"
+	escBold        = "\x1b[1m"
+	escReverse     = "\x1b[7m"
+	escReset       = "\x1b[0m"
+)
+
+// terminal is an interactive terminal, put into raw mode so keys can be read as they are pressed.
+type terminal struct {
+	in, out *os.File
+	restore func() error
+}
+
+// isTerminal returns true if both files are interactive terminals.
+func isTerminal(in, out *os.File) bool {
+	return isatty.IsTerminal(in.Fd()) && isatty.IsTerminal(out.Fd())
+}
+
+// openTerminal puts the terminal into raw mode and switches to its alternate screen.
+// It returns an error if either file is not an interactive terminal, or raw mode is unsupported.
+func openTerminal(in, out *os.File) (*terminal, error) {
+	if !isTerminal(in, out) {
+		return nil, errors.New("not a terminal")
+	}
+	restore, err := makeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not put the terminal into raw mode")
+	}
+	out.WriteString(escAltScreen + escHideCursor + escClearScreen)
+	return &terminal{in: in, out: out, restore: restore}, nil
+}
+
+// size returns the width and height of the terminal, or a standard 80x24 if it cannot be measured.
+func (t *terminal) size() (width, height int) {
+	return terminalSize(t.out)
+}
+
+// terminalSize returns the width and height of the terminal a file is connected to,
+// or a standard 80x24 if it is not a terminal or cannot be measured.
+func terminalSize(f *os.File) (width, height int) {
+	if w, h, err := windowSize(int(f.Fd())); err == nil && w > 0 && h > 0 {
+		return w, h
+	}
+	return 80, 24
+}
+
+// draw replaces the contents of the screen with the frame.
+func (t *terminal) draw(frame string) {
+	t.out.WriteString(escHome + frame)
+}
+
+// Close returns the terminal to the main screen and its original mode.
+func (t *terminal) Close() error {
+	t.out.WriteString(escReset + escShowCursor + escMainScreen)
+	return t.restore()
+}
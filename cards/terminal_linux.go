@@ -0,0 +1,9 @@
+package cards
+
+import "golang.org/x/sys/unix"
+
+// Requests to get and set terminal attributes.
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
@@ -0,0 +1,16 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package cards
+
+import "github.com/pkg/errors"
+
+// makeRaw reports that raw mode is unsupported, so games fall back to the plain command line.
+func makeRaw(fd int) (func() error, error) {
+	return nil, errors.New("raw mode is not supported on this platform")
+}
+
+// windowSize reports that the terminal cannot be measured.
+func windowSize(fd int) (width, height int, err error) {
+	return 0, 0, errors.New("window size is not supported on this platform")
+}
@@ -0,0 +1,116 @@
+package cards
+
+import (
+	"context"
+	mrand "math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Clock tells the time. Games read the time from a Clock, so tests can control it.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is a Clock that tells the real time.
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// NNTimeoutAction is what happens to a 99 player who runs out of time on their turn.
+type NNTimeoutAction string
+
+const (
+	TimeoutRandomCard  NNTimeoutAction = "random"  // a random card from their hand is played for them
+	TimeoutSafestCard  NNTimeoutAction = "safest"  // the card the cautious strategy would choose is played for them
+	TimeoutForfeitLife NNTimeoutAction = "forfeit" // they lose a life, and a new round begins
+)
+
+// SetClock replaces the clock used to time turns, which is the system clock by default.
+func (mgr *NNGameManager) SetClock(c Clock) {
+	mgr.clock = c
+}
+
+// now returns the time according to the game's clock.
+func (mgr *NNGameManager) now() time.Time {
+	if mgr.clock == nil {
+		mgr.clock = systemClock{}
+	}
+	return mgr.clock.Now()
+}
+
+// beginTurn starts the turn timer for the current player.
+func (mgr *NNGameManager) beginTurn() {
+	mgr.turnStart = mgr.now()
+	mgr.turn++
+	mgr.warned = false
+}
+
+// TurnDeadline returns the time by which the current player must play.
+// It returns false if turns have no time limit.
+func (mgr *NNGameManager) TurnDeadline() (time.Time, bool) {
+	if !mgr.playing || mgr.settings.TurnLimit <= 0 {
+		return time.Time{}, false
+	}
+	return mgr.turnStart.Add(mgr.settings.TurnLimit), true
+}
+
+// CheckTurnTimer enforces the turn time limit, and should be called regularly by whoever runs the game,
+// such as when a ticker fires or the TurnDeadline passes.
+// Once the current player is within the warning period of the limit, a warning Event is emitted.
+// Once they have run out of time, a timeout Event is emitted and the timeout action is taken for them.
+// The timer is stopped while the game is paused.
+func (mgr *NNGameManager) CheckTurnTimer() error {
+	deadline, ok := mgr.TurnDeadline()
+	if !ok || mgr.paused {
+		return nil
+	}
+	seat := mgr.table.CurrSeat()
+	remaining := deadline.Sub(mgr.now())
+	if remaining > 0 {
+		if warning := mgr.settings.TurnWarning; warning > 0 && remaining <= warning && !mgr.warned {
+			mgr.warned = true
+			mgr.events.Emit(Event{Game: mgr.settings.Game(), Type: EventTurnWarning, Seat: seat, Count: mgr.count, Remaining: remaining})
+		}
+		return nil
+	}
+
+	mgr.emit(EventTimedOut, seat, nil)
+	p := mgr.CurrPlayer()
+	if len(p.Hand()) == 0 {
+		// With no card to play for them, the player can only forfeit a life.
+		mgr.loseLife(p)
+		return nil
+	}
+	// A card played when time runs out cannot be taken back, or the turn limit could be dodged.
+	defer func() { mgr.undo = nil }()
+	switch mgr.settings.OnTimeout {
+	case TimeoutRandomCard:
+		r := mrand.New(mrand.NewSource(mgr.now().UnixNano()))
+		return playCardAt(p, r.Intn(len(p.Hand())))
+	case TimeoutSafestCard:
+		i, err := NewCautiousStrategy().Choose(context.Background(), mgr.View(seat))
+		if err != nil {
+			return err
+		}
+		return playCardAt(p, i)
+	case TimeoutForfeitLife:
+		mgr.loseLife(p)
+		return nil
+	default:
+		return errors.Errorf("unknown timeout action %q", mgr.settings.OnTimeout)
+	}
+}
+
+// Valid returns true if the action is one of the known timeout actions.
+func (a NNTimeoutAction) Valid() bool {
+	switch a {
+	case TimeoutRandomCard, TimeoutSafestCard, TimeoutForfeitLife:
+		return true
+	}
+	return false
+}
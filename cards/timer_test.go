@@ -0,0 +1,139 @@
+package cards
+
+import (
+	"shuffle/utils"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when told to. It is safe to use from any goroutine.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now returns the fake time.
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the fake time forward.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTimedGame starts a game of 99 between three players with a 30 second turn limit,
+// a 10 second warning and the given timeout action.
+func newTimedGame(action NNTimeoutAction) (*NNGameManager, []*NNPlayer, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2021, time.December, 25, 9, 0, 0, 0, time.UTC)}
+	settings := *NNDefaultSettings
+	settings.TurnLimit = 30 * time.Second
+	settings.TurnWarning = 10 * time.Second
+	settings.OnTimeout = action
+
+	mgr := new(NNGameManager)
+	mgr.SetClock(clock)
+	players := []*NNPlayer{NewNNPlayer("Alice"), NewNNPlayer("Bob"), NewNNPlayer("Charlie")}
+	mgr.StartGame(players, 0, &settings)
+	players[0].ReplaceHand(Hand{NewCard(King, Hearts), NewCard(Two, Clubs), NewCard(Ten, Spades)})
+	mgr.count = 50
+	return mgr, players, clock
+}
+
+// lastEvent returns the type of the latest Event emitted by the game.
+func lastEvent(mgr *NNGameManager) EventType {
+	history := mgr.Events().History()
+	return history[len(history)-1].Type
+}
+
+func TestTurnTimerWarning(t *testing.T) {
+	mgr, players, clock := newTimedGame(TimeoutSafestCard)
+	deadline, ok := mgr.TurnDeadline()
+	utils.Error(t, ok, true, "turns have a deadline")
+	utils.Error(t, deadline, clock.now.Add(30*time.Second), "deadline")
+
+	clock.Advance(15 * time.Second)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, lastEvent(mgr), EventDealt, "no warning yet")
+
+	clock.Advance(5 * time.Second)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, lastEvent(mgr), EventTurnWarning, "warning")
+	history := mgr.Events().History()
+	utils.Error(t, history[len(history)-1].Remaining, 10*time.Second, "time remaining")
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, len(mgr.Events().History()), len(history), "a single warning")
+
+	utils.Fatal(t, players[0].Play(Hand{NewCard(Two, Clubs)}), nil)
+	deadline, _ = mgr.TurnDeadline()
+	utils.Error(t, deadline, clock.now.Add(30*time.Second), "deadline for the next player")
+}
+
+func TestTurnTimerActions(t *testing.T) {
+	mgr, players, clock := newTimedGame(TimeoutSafestCard)
+	clock.Advance(30 * time.Second)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, mgr.Count(), 52, "count after the safest card")
+	utils.Error(t, mgr.CurrPlayer().ID(), players[1].ID(), "current player")
+	var types []EventType
+	for _, e := range mgr.Events().Since(1) {
+		types = append(types, e.Type)
+	}
+	utils.Error(t, types[1:], []EventType{EventTimedOut, EventCardPlayed, EventDrew}, "events after the deal")
+
+	mgr, players, clock = newTimedGame(TimeoutRandomCard)
+	clock.Advance(time.Minute)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, len(players[0].Hand()), 3, "cards after the random card and pickup")
+	utils.Error(t, mgr.CurrPlayer().ID(), players[1].ID(), "current player")
+
+	mgr, players, clock = newTimedGame(TimeoutForfeitLife)
+	clock.Advance(time.Minute)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, mgr.Lives(players[0].ID()), NNDefaultSettings.LivesPerPlayer-1, "lives after forfeiting")
+	utils.Error(t, mgr.Round(), 2, "round after forfeiting")
+	utils.Error(t, mgr.CurrPlayer().ID(), players[1].ID(), "leader of the next round")
+}
+
+func TestTurnTimerFallbacks(t *testing.T) {
+	mgr, _, clock := newTimedGame("")
+	clock.Advance(time.Minute)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, mgr.Count(), 52, "the safest card is played by default")
+
+	mgr, players, clock := newTimedGame(TimeoutRandomCard)
+	players[0].ReplaceHand(Hand{})
+	clock.Advance(time.Minute)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, mgr.Lives(players[0].ID()), NNDefaultSettings.LivesPerPlayer-1, "lives after timing out with no cards")
+}
+
+func TestTurnTimerUndo(t *testing.T) {
+	mgr, players, clock := newTimedGame(TimeoutSafestCard)
+	mgr.settings.Undo = true
+	start := clock.now
+	clock.Advance(20 * time.Second)
+	utils.Fatal(t, players[0].Play(Hand{NewCard(Two, Clubs)}), nil)
+	utils.Fatal(t, mgr.Undo(players[0]), nil)
+	deadline, _ := mgr.TurnDeadline()
+	utils.Error(t, deadline, start.Add(30*time.Second), "taking a card back keeps the deadline")
+
+	clock.Advance(10 * time.Second)
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, mgr.CurrPlayer().ID(), players[1].ID(), "current player")
+	utils.Error(t, mgr.CanUndo(players[0]), false, "a card played on timeout cannot be taken back")
+	utils.Error(t, mgr.Undo(players[0]) != nil, true, "taking back a card played on timeout")
+}
+
+func TestNoTurnTimer(t *testing.T) {
+	mgr, _ := newTestGame("Alice", "Bob")
+	_, ok := mgr.TurnDeadline()
+	utils.Error(t, ok, false, "no deadline")
+	utils.Fatal(t, mgr.CheckTurnTimer(), nil)
+	utils.Error(t, lastEvent(mgr), EventDealt, "no timeout")
+}
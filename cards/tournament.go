@@ -0,0 +1,378 @@
+package cards
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+// Tournament formats decide which entrants share a table in each round.
+const (
+	// TournamentRoundRobin seats every combination of entrants together once per round.
+	TournamentRoundRobin = "roundrobin"
+	// TournamentSwiss seats entrants with similar ratings together, re-pairing them after every round
+	// and avoiding rematches where it can.
+	TournamentSwiss = "swiss"
+)
+
+// Elo rating parameters for tournaments.
+const (
+	initialRating = 1500.0
+	ratingK       = 32.0
+)
+
+// TournamentConfig describes a tournament of 99 between robot strategies and external bots.
+type TournamentConfig struct {
+	Entrants []string        // the strategy played by each entrant, including bots named with BotPrefix
+	Seats    int             // the number of players at each table; defaults to 4, or fewer if there are fewer entrants
+	Format   string          // TournamentRoundRobin or TournamentSwiss
+	Rounds   int             // the number of rounds to play; defaults to 1 for round robin and 3 for Swiss
+	Seed     int64           // the master seed, from which every shuffle and strategy seed is derived
+	Settings *NNGameSettings // the rules of every game; take-backs are never allowed in tournaments
+}
+
+// Standing is an entrant's place on the leaderboard at the end of a tournament.
+type Standing struct {
+	Name      string  `json:"name"`
+	Rating    float64 `json:"rating"`
+	Low       float64 `json:"low"`  // the lower bound of the 95% confidence interval of the rating
+	High      float64 `json:"high"` // the upper bound of the 95% confidence interval of the rating
+	Games     int     `json:"games"`
+	Wins      int     `json:"wins"`
+	MeanPlace float64 `json:"mean_place"`
+}
+
+// TournamentReport is the leaderboard of a tournament, best rated first.
+type TournamentReport struct {
+	Format    string     `json:"format"`
+	Games     int        `json:"games"`
+	Standings []Standing `json:"standings"`
+}
+
+// entrant tracks a single strategy over the course of a tournament.
+type entrant struct {
+	index    int
+	name     string
+	strategy NNStrategy
+	rating   float64
+	games    int
+	wins     int
+	places   int         // the sum of the entrant's places, counting from 1
+	score    float64     // the number of opponents the entrant finished ahead of, counting ties as half
+	meetings int         // the number of opponents the entrant has played against
+	met      map[int]int // the number of tables the entrant has shared with each other entrant, by index
+}
+
+// RunTournament plays a tournament of 99 and returns the leaderboard.
+// Every game at a table is played once for each seat, rotating the seating order,
+// and ratings are updated after each game with a multiplayer Elo, which scores every pair
+// of players at the table as a single Elo game won by whoever finished ahead.
+// Games are played one at a time, so a tournament is reproducible from its seed.
+func RunTournament(ctx context.Context, cfg TournamentConfig) (*TournamentReport, error) {
+	if len(cfg.Entrants) < 2 {
+		return nil, errors.New("at least two entrants are needed")
+	}
+	seats := cfg.Seats
+	if seats == 0 {
+		seats = 4
+		if len(cfg.Entrants) < seats {
+			seats = len(cfg.Entrants)
+		}
+	}
+	if seats < 2 || seats > len(cfg.Entrants) {
+		return nil, errors.Errorf("cannot seat %d players per table with %d entrants", seats, len(cfg.Entrants))
+	}
+	rounds := cfg.Rounds
+	if rounds <= 0 {
+		rounds = 1
+		if cfg.Format == TournamentSwiss {
+			rounds = 3
+		}
+	}
+	var pair func(entrants []*entrant) [][]*entrant
+	switch cfg.Format {
+	case TournamentRoundRobin, "":
+		pair = func(entrants []*entrant) [][]*entrant { return roundRobinTables(entrants, seats) }
+	case TournamentSwiss:
+		pair = func(entrants []*entrant) [][]*entrant { return swissTables(entrants, seats) }
+	default:
+		return nil, errors.Errorf("unknown tournament format %q", cfg.Format)
+	}
+
+	settings := *NNDefaultSettings
+	if cfg.Settings != nil {
+		settings = *cfg.Settings
+	}
+	settings.Undo = false
+
+	entrants, err := newEntrants(cfg.Entrants, cfg.Seed)
+	defer func() {
+		for _, e := range entrants {
+			closeStrategy(e.strategy)
+		}
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TournamentReport{Format: cfg.Format}
+	if report.Format == "" {
+		report.Format = TournamentRoundRobin
+	}
+	for round := 0; round < rounds; round++ {
+		for _, table := range pair(entrants) {
+			for rotation := range table {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				seated := make([]*entrant, len(table))
+				strategies := make([]NNStrategy, len(table))
+				for seat := range table {
+					seated[seat] = table[(seat+rotation)%len(table)]
+					strategies[seat] = seated[seat].strategy
+				}
+				game, err := playRobotGame(ctx, &settings, strategies, NewRngAt(cfg.Seed+int64(report.Games)))
+				if err != nil {
+					return nil, err
+				}
+				rate(seated, game.places(len(seated)))
+				report.Games++
+			}
+			meet(table)
+		}
+	}
+
+	for _, e := range entrants {
+		report.Standings = append(report.Standings, e.standing())
+	}
+	sort.SliceStable(report.Standings, func(i, j int) bool {
+		return report.Standings[i].Rating > report.Standings[j].Rating
+	})
+	return report, nil
+}
+
+// newEntrants creates an entrant for each strategy name, numbering repeated names to tell them apart.
+func newEntrants(names []string, seed int64) ([]*entrant, error) {
+	seen := make(map[string]int)
+	var entrants []*entrant
+	for i, name := range names {
+		s, err := NewNNStrategy(name, seed+int64(i))
+		if err != nil {
+			return entrants, err
+		}
+		seen[name]++
+		label := name
+		if seen[name] > 1 {
+			label = fmt.Sprintf("%s (%d)", name, seen[name])
+		}
+		entrants = append(entrants, &entrant{index: i, name: label, strategy: s, rating: initialRating})
+	}
+	return entrants, nil
+}
+
+// roundRobinTables returns every combination of the given number of entrants, in lexicographic order.
+func roundRobinTables(entrants []*entrant, seats int) [][]*entrant {
+	var tables [][]*entrant
+	var choose func(start int, table []*entrant)
+	choose = func(start int, table []*entrant) {
+		if len(table) == seats {
+			tables = append(tables, append([]*entrant{}, table...))
+			return
+		}
+		for i := start; i <= len(entrants)-(seats-len(table)); i++ {
+			choose(i+1, append(table, entrants[i]))
+		}
+	}
+	choose(0, nil)
+	return tables
+}
+
+// swissTables seats entrants in order of rating, filling each table before moving on to the next.
+// The entrants are split across as few tables as will seat them, as evenly as possible, so no table seats more than
+// the given number. With two seats per table, an odd entrant out makes one table of three instead.
+// Entrants are then swapped between neighbouring tables to avoid rematches, as avoidRematches does.
+func swissTables(entrants []*entrant, seats int) [][]*entrant {
+	ranked := append([]*entrant{}, entrants...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].rating > ranked[j].rating })
+	count := (len(ranked) + seats - 1) / seats
+	if count > 1 && len(ranked) < 2*count {
+		count = len(ranked) / 2
+	}
+	var tables [][]*entrant
+	for i := 0; i < count; i++ {
+		n := len(ranked) / (count - i)
+		if len(ranked)%(count-i) > 0 {
+			n++
+		}
+		tables, ranked = append(tables, ranked[:n]), ranked[n:]
+	}
+	avoidRematches(tables)
+	return tables
+}
+
+// avoidRematches swaps entrants between neighbouring tables whenever the swap means fewer pairs of entrants meet again.
+// Swaps between the lowest rated entrants of one table and the highest rated of the next are tried first,
+// so that entrants stay as close to their rating as they can.
+func avoidRematches(tables [][]*entrant) {
+	for improved := true; improved; {
+		improved = false
+		for t := 0; t+1 < len(tables) && !improved; t++ {
+			a, b := tables[t], tables[t+1]
+			before := rematches(a) + rematches(b)
+			for i := len(a) - 1; i >= 0 && !improved; i-- {
+				for j := 0; j < len(b) && !improved; j++ {
+					a[i], b[j] = b[j], a[i]
+					if improved = rematches(a)+rematches(b) < before; !improved {
+						a[i], b[j] = b[j], a[i]
+					}
+				}
+			}
+		}
+	}
+}
+
+// rematches returns the number of times the pairs of entrants at a table have already shared a table.
+func rematches(table []*entrant) int {
+	n := 0
+	for i, a := range table {
+		for _, b := range table[i+1:] {
+			n += a.met[b.index]
+		}
+	}
+	return n
+}
+
+// meet records that the entrants at a table have shared it.
+func meet(table []*entrant) {
+	for _, a := range table {
+		if a.met == nil {
+			a.met = make(map[int]int)
+		}
+		for _, b := range table {
+			if a != b {
+				a.met[b.index]++
+			}
+		}
+	}
+}
+
+// places returns where each seat finished the game, counting from 1.
+// The winner finishes first, and eliminated players finish in the reverse order they were eliminated.
+// If the game was unfinished, the players still in it share first place.
+func (r *simRecorder) places(seats int) []int {
+	places := make([]int, seats)
+	for i, seat := range r.out {
+		places[seat] = seats - i
+	}
+	if r.winner != NoSeat {
+		places[r.winner] = 1
+	}
+	for seat, place := range places {
+		if place == 0 {
+			places[seat] = 1
+		}
+	}
+	return places
+}
+
+// rate updates the ratings of the players at a table after a game.
+// Each pair of players is scored as an Elo game, and each player's rating moves by the sum of
+// their pairwise adjustments, scaled so a game moves ratings as much as a single two-player game.
+func rate(table []*entrant, places []int) {
+	deltas := make([]float64, len(table))
+	k := ratingK / float64(len(table)-1)
+	for i, a := range table {
+		for j, b := range table {
+			if i == j {
+				continue
+			}
+			score := 0.5
+			if places[i] < places[j] {
+				score = 1
+			} else if places[i] > places[j] {
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (b.rating-a.rating)/400))
+			deltas[i] += k * (score - expected)
+			a.score += score
+			a.meetings++
+		}
+	}
+	for i, e := range table {
+		e.rating += deltas[i]
+		e.games++
+		e.places += places[i]
+		if places[i] == 1 && len(places) > 1 {
+			e.wins++
+		}
+	}
+}
+
+// standing summarizes the entrant's tournament.
+// The confidence interval is that of the entrant's performance rating, taken from the fraction
+// of opponents they finished ahead of, and is centred on their Elo rating.
+func (e *entrant) standing() Standing {
+	st := Standing{Name: e.name, Rating: e.rating, Low: e.rating, High: e.rating, Games: e.games, Wins: e.wins}
+	if e.games > 0 {
+		st.MeanPlace = float64(e.places) / float64(e.games)
+	}
+	if e.meetings > 0 {
+		s := e.score / float64(e.meetings)
+		margin := 1.96 * math.Sqrt(s*(1-s)/float64(e.meetings))
+		st.Low = e.rating + performance(s-margin) - performance(s)
+		st.High = e.rating + performance(s+margin) - performance(s)
+	}
+	return st
+}
+
+// performance converts a fraction of Elo games won to a rating difference from the opposition.
+func performance(s float64) float64 {
+	s = math.Max(0.001, math.Min(0.999, s))
+	return -400 * math.Log10(1/s-1)
+}
+
+// rows flattens the leaderboard into columns, in a stable order.
+func (r *TournamentReport) rows() [][]string {
+	f := func(x float64) string { return strconv.FormatFloat(x, 'f', 0, 64) }
+	rows := [][]string{{"rank", "name", "rating", "low", "high", "games", "wins", "mean place"}}
+	for i, st := range r.Standings {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1), st.Name, f(st.Rating), f(st.Low), f(st.High),
+			strconv.Itoa(st.Games), strconv.Itoa(st.Wins), strconv.FormatFloat(st.MeanPlace, 'f', 2, 64),
+		})
+	}
+	return rows
+}
+
+// WriteText writes the leaderboard as a human-readable table.
+func (r *TournamentReport) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "%s tournament, %d games\n\n", r.Format, r.Games)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tentrant\trating\t95% CI\tgames\twins\tmean place")
+	for _, row := range r.rows()[1:] {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s–%s\t%s\t%s\t%s\n", row[0], row[1], row[2], row[3], row[4], row[5], row[6], row[7])
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the leaderboard as CSV, with one entrant per row.
+func (r *TournamentReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.WriteAll(r.rows())
+	return cw.Error()
+}
+
+// WriteJSON writes the leaderboard as JSON.
+func (r *TournamentReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,114 @@
+package cards
+
+import (
+	"bytes"
+	"context"
+	"shuffle/utils"
+	"strings"
+	"testing"
+)
+
+func TestRunTournament(t *testing.T) {
+	cfg := TournamentConfig{
+		Entrants: []string{"cautious", "aggressive", "random", "random"},
+		Seats:    3,
+		Rounds:   2,
+		Seed:     2021,
+	}
+	report, err := RunTournament(context.Background(), cfg)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, report.Games, 4*3*2, "games for 4 tables of 3, twice")
+	utils.Fatal(t, len(report.Standings), 4, "standings")
+	utils.Error(t, report.Standings[3].Name, "random (2)", "repeated entrant")
+
+	total := 0.0
+	for i, st := range report.Standings {
+		utils.Error(t, st.Games, 3*3*2, st.Name+" games")
+		utils.Error(t, st.Low <= st.Rating && st.Rating <= st.High, true, st.Name+" rating within its interval")
+		if i > 0 {
+			utils.Error(t, st.Rating <= report.Standings[i-1].Rating, true, "standings in order")
+		}
+		total += st.Rating
+	}
+	utils.Error(t, int(total+0.5), 4*int(initialRating), "sum of ratings")
+
+	again, err := RunTournament(context.Background(), cfg)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, again, report, "for a repeated tournament")
+}
+
+func TestRunTournamentSwiss(t *testing.T) {
+	cfg := TournamentConfig{
+		Entrants: []string{"cautious", "aggressive", "random", "random", "cautious"},
+		Seats:    2,
+		Format:   TournamentSwiss,
+		Seed:     1,
+	}
+	report, err := RunTournament(context.Background(), cfg)
+	utils.Fatal(t, err, nil)
+	utils.Error(t, report.Games, 3*(2+3), "games for a table of 2 and a table of 3, three times")
+
+	var text, csv bytes.Buffer
+	utils.Fatal(t, report.WriteText(&text), nil)
+	utils.Error(t, strings.HasPrefix(text.String(), "swiss tournament, 15 games"), true, "text has a title")
+	utils.Fatal(t, report.WriteCSV(&csv), nil)
+	utils.Error(t, strings.Count(csv.String(), "\n"), 6, "csv rows")
+}
+
+func TestRunTournamentInvalid(t *testing.T) {
+	for _, cfg := range []TournamentConfig{
+		{Entrants: []string{"cautious"}},
+		{Entrants: []string{"cautious", "random"}, Seats: 3},
+		{Entrants: []string{"cautious", "random"}, Format: "knockout"},
+		{Entrants: []string{"cautious", "psychic"}},
+	} {
+		_, err := RunTournament(context.Background(), cfg)
+		utils.Error(t, err != nil, true, "error for an invalid tournament")
+	}
+}
+
+func TestSwissTables(t *testing.T) {
+	var entrants []*entrant
+	for i := 0; i < 7; i++ {
+		entrants = append(entrants, &entrant{index: i, rating: float64(i)})
+	}
+	var sizes, leaders []int
+	for _, table := range swissTables(entrants, 3) {
+		sizes = append(sizes, len(table))
+		leaders = append(leaders, table[0].index)
+	}
+	utils.Error(t, sizes, []int{3, 2, 2}, "table sizes")
+	utils.Error(t, leaders, []int{6, 3, 1}, "highest rated at each table")
+
+	sizes = nil
+	for _, table := range swissTables(entrants[:5], 2) {
+		sizes = append(sizes, len(table))
+	}
+	utils.Error(t, sizes, []int{3, 2}, "table sizes with an odd number of entrants at tables of two")
+
+	meet([]*entrant{entrants[6], entrants[5]})
+	var tables [][]int
+	for _, table := range swissTables(entrants[3:], 2) {
+		var indices []int
+		for _, e := range table {
+			indices = append(indices, e.index)
+		}
+		tables = append(tables, indices)
+	}
+	utils.Error(t, tables, [][]int{{6, 4}, {5, 3}}, "tables avoiding a rematch")
+}
+
+func TestRate(t *testing.T) {
+	a, b, c := &entrant{rating: 1500}, &entrant{rating: 1500}, &entrant{rating: 1500}
+	rate([]*entrant{a, b, c}, []int{2, 1, 3})
+	utils.Error(t, []float64{a.rating, b.rating, c.rating}, []float64{1500, 1516, 1484})
+	utils.Error(t, []int{a.wins, b.wins, c.wins}, []int{0, 1, 0})
+	utils.Error(t, a.score, 1.0, "pairwise score")
+}
+
+func TestPlaces(t *testing.T) {
+	r := &simRecorder{out: []int{2, 0}, winner: NoSeat}
+	utils.Error(t, r.places(4), []int{3, 1, 4, 1}, "for an unfinished game")
+	r.out, r.winner = append(r.out, 1), 3
+	utils.Error(t, r.places(4), []int{3, 2, 4, 1})
+}
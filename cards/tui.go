@@ -0,0 +1,446 @@
+package cards
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"shuffle/render"
+	"shuffle/utils"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Timings of the full-screen interface.
+const (
+	tuiRobotDelay = 700 * time.Millisecond // how long robots appear to think, so people can follow their plays
+	tuiTick       = 250 * time.Millisecond // how often the turn timer is checked
+	tuiLogSize    = 50                     // how many log lines are kept
+)
+
+// key is a key pressed on the keyboard: either a printable character or one of the special keys below.
+type key rune
+
+const (
+	keyLeft key = -1 - iota
+	keyRight
+	keyUp
+	keyDown
+	keyEnter
+	keyQuit
+)
+
+// readKeys reads key presses from a terminal in raw mode and sends them on the returned channel,
+// until the input is closed.
+func readKeys(r io.Reader) <-chan key {
+	keys := make(chan key)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 32)
+		for {
+			n, err := r.Read(buf)
+			for _, k := range parseKeys(buf[:n]) {
+				keys <- k
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+// parseKeys converts raw terminal input into key presses.
+// Arrow keys arrive as escape sequences; other escape sequences are ignored.
+func parseKeys(b []byte) []key {
+	var keys []key
+	for len(b) > 0 {
+		switch {
+		case len(b) >= 3 && b[0] == 0x1b && (b[1] == '[' || b[1] == 'O'):
+			switch b[2] {
+			case 'A':
+				keys = append(keys, keyUp)
+			case 'B':
+				keys = append(keys, keyDown)
+			case 'C':
+				keys = append(keys, keyRight)
+			case 'D':
+				keys = append(keys, keyLeft)
+			}
+			b = b[3:]
+		case b[0] == '\r' || b[0] == '\n':
+			keys = append(keys, keyEnter)
+			b = b[1:]
+		case b[0] == 3 || b[0] == 4: // Ctrl-C and Ctrl-D
+			keys = append(keys, keyQuit)
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r >= ' ' {
+				keys = append(keys, key(r))
+			}
+			b = b[size:]
+		}
+	}
+	return keys
+}
+
+// nnScreen is the full-screen interface to a game of 99, shared by everyone at the terminal.
+// It shows the count, the players seated in a circle, the current player's hand and a log of recent plays.
+type nnScreen struct {
+	mgr      *NNGameManager
+	selected int
+	log      []string
+	message  string     // feedback on the latest key press, such as an invalid play
+	hints    []CardHint // the hints for the current player's hand, if hints are shown
+	hintTurn int        // the turn the hints were worked out for
+}
+
+// newNNScreen creates the interface for the game run by the manager.
+func newNNScreen(mgr *NNGameManager) *nnScreen {
+	return &nnScreen{mgr: mgr}
+}
+
+// record adds a description of an Event to the log.
+func (s *nnScreen) record(e Event) {
+	if line, ok := describeNNEvent(tableNames(s.mgr.table), e); ok {
+		s.log = append(s.log, line)
+		if len(s.log) > tuiLogSize {
+			s.log = s.log[len(s.log)-tuiLogSize:]
+		}
+	}
+}
+
+// describeNNEvent describes a public Event of a game of 99 in a short sentence.
+// It returns false for Events not worth describing, such as players drawing cards.
+// The names are those of the players in seating order.
+func describeNNEvent(names []string, e Event) (string, bool) {
+	name := func() string {
+		if 0 <= e.Seat && e.Seat < len(names) {
+			return names[e.Seat]
+		}
+		return translate("Someone")
+	}
+	switch e.Type {
+	case EventDealt:
+		return translate("New round: %v turned up, count %d", inlineString(e.Cards), e.Count), true
+	case EventCardPlayed:
+		return translate("%s plays %v, count %d", name(), inlineString(e.Cards), e.Count), true
+	case EventReversed:
+		return translate("Play reverses direction"), true
+	case EventBusted:
+		return translate("%s busts at %d!", name(), e.Count), true
+	case EventLifeLost:
+		return translate("%s has %d lives left", name(), e.Lives), true
+	case EventEliminated:
+		return translate("%s is out of the game", name()), true
+	case EventWon:
+		return translate("%s wins the game!", name()), true
+	case EventUndone:
+		return translate("%s takes back %v, count %d", name(), inlineString(e.Cards), e.Count), true
+	case EventTurnWarning:
+		return translate("%s has %d seconds left", name(), int(math.Ceil(e.Remaining.Seconds()))), true
+	case EventTimedOut:
+		return translate("%s ran out of time", name()), true
+	case EventDisconnected:
+		return translate("%s lost their connection", name()), true
+	case EventReconnected:
+		return translate("%s is back", name()), true
+	case EventReplaced:
+		return translate("A robot plays for %s", name()), true
+	case EventChat:
+		return e.Name + ": " + e.Text, true
+	case EventEmote:
+		return e.Name + ": " + translate(e.Text), true
+	case EventMuted:
+		return translate("The host has muted %s", e.Name), true
+	case EventUnmuted:
+		return translate("%s may chat again", e.Name), true
+	case EventPaused:
+		return translate("The host has paused the game"), true
+	case EventResumed:
+		return translate("The game resumes"), true
+	case EventKicked:
+		return translate("The host has removed %s from the table", e.Name), true
+	case EventRobotAdded:
+		if e.Name == "" {
+			return translate("A robot sits down at the table"), true
+		}
+		return translate("%s sits down at the table", e.Name), true
+	case EventRobotRemoved:
+		if e.Name == "" {
+			return translate("A robot leaves the table"), true
+		}
+		return translate("%s leaves the table", e.Name), true
+	case EventRulesChanged:
+		if e.Name == "" {
+			return translate("The new rules apply from this round"), true
+		}
+		return translate("The host has changed the rules"), true
+	}
+	return "", false
+}
+
+// run plays the game on the terminal until it ends or the players quit.
+func (s *nnScreen) run(term *terminal) {
+	keys := readKeys(term.in)
+	ticker := time.NewTicker(tuiTick)
+	defer ticker.Stop()
+
+	for s.mgr.playing {
+		term.draw(s.render(term.size()))
+		p := s.mgr.CurrPlayer()
+		if nn, ok := p.(*NNPlayer); ok && nn.Robot() {
+			select {
+			case k, ok := <-keys:
+				if !ok || k == keyQuit || k == 'q' {
+					s.mgr.EndGame()
+				}
+			case <-time.After(tuiRobotDelay):
+				s.report(s.mgr.PlayTurn(context.Background()))
+			}
+			continue
+		}
+		select {
+		case k, ok := <-keys:
+			if !ok {
+				s.mgr.EndGame()
+			} else {
+				s.press(p, k)
+			}
+		case <-ticker.C:
+			s.report(s.mgr.CheckTurnTimer())
+		}
+	}
+
+	s.message = translate("Game over. Press any key to leave.")
+	term.draw(s.render(term.size()))
+	<-keys
+}
+
+// press acts on a key pressed by the current player.
+func (s *nnScreen) press(p Player, k key) {
+	s.message = ""
+	hand := len(p.Hand())
+	if hand == 0 {
+		return
+	}
+	switch {
+	case k == keyLeft || k == keyUp:
+		s.selected = (s.selected + hand - 1) % hand
+	case k == keyRight || k == keyDown:
+		s.selected = (s.selected + 1) % hand
+	case k == keyEnter || k == ' ':
+		s.report(playCardAt(p, s.selected))
+	case '1' <= k && k <= '9':
+		if i := int(k - '1'); i < hand {
+			s.selected = i
+			s.report(playCardAt(p, i))
+		}
+	case k == 'u':
+		if last := s.mgr.lastHumanPlay(); last != nil {
+			s.report(s.mgr.Undo(last))
+		} else {
+			s.message = translate("There is nothing to take back.")
+		}
+	case k == 'q' || k == keyQuit:
+		s.mgr.EndGame()
+	}
+}
+
+// report shows an error from the latest action, if any.
+func (s *nnScreen) report(err error) {
+	if err != nil {
+		s.message = fmt.Sprint(errors.Cause(err))
+	}
+}
+
+// render draws a frame of the interface to fit the given size.
+func (s *nnScreen) render(width, height int) string {
+	mgr := s.mgr
+	var lines []string
+	title := " 99  " + translate("Round %d", mgr.round)
+	if deadline, ok := mgr.TurnDeadline(); ok {
+		title += "  " + translate("%ds left", int(math.Ceil(deadline.Sub(mgr.now()).Seconds())))
+	}
+	lines = append(lines, escBold+title+escReset, "")
+	lines = append(lines, s.renderCircle(utils.Min(width, 72))...)
+	lines = append(lines, "")
+
+	if p := mgr.CurrPlayer(); p != nil && mgr.playing {
+		hand := p.Hand()
+		if s.selected >= len(hand) {
+			s.selected = 0
+		}
+		lines = append(lines, " "+translate("%s's hand:", p.Name()))
+		if nn, ok := p.(*NNPlayer); ok && nn.Robot() {
+			lines = append(lines, renderFaces(make(Hand, len(hand)), NoSeat)...)
+		} else {
+			lines = append(lines, renderFaces(hand, s.selected)...)
+			if h, ok := s.hint(p); ok {
+				lines = append(lines, " "+strings.TrimSpace(describeHint(h)))
+			}
+		}
+	} else {
+		lines = append(lines, make([]string, render.BoxHeight+2)...)
+	}
+	lines = append(lines, " "+translate("←/→ choose  enter play  1-9 play  u take back  q quit"), " "+s.message, "")
+
+	if room := height - len(lines); room > 0 {
+		start := utils.Max(len(s.log)-room, 0)
+		for _, line := range s.log[start:] {
+			if runes := []rune(line); len(runes) > width-2 {
+				line = string(runes[:utils.Max(width-2, 0)]) + escReset
+			}
+			lines = append(lines, " "+line)
+		}
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		lines[i] = line + escClearLine
+	}
+	return strings.Join(lines, "\n") + escClearBelow
+}
+
+// hint returns the hint for the card the current player has selected, if hints are shown.
+// Hints are worked out once a turn, rather than for every frame.
+func (s *nnScreen) hint(p Player) (CardHint, bool) {
+	if !s.mgr.hints {
+		return CardHint{}, false
+	}
+	if s.hints == nil || s.hintTurn != s.mgr.turn {
+		s.hints, s.hintTurn = Analyze(s.mgr.View(p.ID())), s.mgr.turn
+	}
+	if s.selected >= len(s.hints) {
+		return CardHint{}, false
+	}
+	return s.hints[s.selected], true
+}
+
+// renderCircle draws the players seated around an ellipse, with the count in large digits at its centre
+// and an arrow showing the direction of play beneath it. The current player's seat is highlighted.
+func (s *nnScreen) renderCircle(width int) []string {
+	const rows = 13
+	grid := make([][]string, rows)
+	for y := range grid {
+		grid[y] = make([]string, width)
+		for x := range grid[y] {
+			grid[y][x] = " "
+		}
+	}
+	write := func(x, y int, text string, style string) {
+		cells := []string{}
+		for _, r := range text {
+			cells = append(cells, string(r))
+		}
+		x = utils.Max(0, utils.Min(x, width-len(cells)))
+		for i, c := range cells {
+			if 0 <= y && y < rows && x+i < width {
+				grid[y][x+i] = c
+			}
+		}
+		if style != "" && len(cells) > 0 && x < width {
+			grid[y][x] = style + grid[y][x]
+			last := utils.Min(x+len(cells), width) - 1
+			grid[y][last] += escReset
+		}
+	}
+
+	mgr := s.mgr
+	cx, cy := width/2, rows/2
+	for y, line := range bigNumber(mgr.count) {
+		write(cx-utf8.RuneCountInString(line)/2, cy-3+y, line, escBold)
+	}
+	arrow := "↻ " + translate("clockwise")
+	if mgr.table.Direction() < 0 {
+		arrow = "↺ " + translate("counterclockwise")
+	}
+	write(cx-utf8.RuneCountInString(arrow)/2, cy+3, arrow, "")
+
+	players := mgr.table.Players()
+	rx, ry := float64(width/2-12), float64(rows/2)
+	for seat, p := range players {
+		angle := 2*math.Pi*float64(seat)/float64(len(players)) - math.Pi/2
+		x := cx + int(math.Round(rx*math.Cos(angle)))
+		y := cy + int(math.Round(ry*math.Sin(angle)))
+		label := p.Name() + " " + hearts(mgr.Lives(seat), mgr.settings.LivesPerPlayer)
+		style := ""
+		if !mgr.table.Active(seat) {
+			label = p.Name() + " " + translate("out")
+			style = "\x1b[2m"
+		} else if seat == mgr.table.CurrSeat() && mgr.playing {
+			label = "▶ " + label
+			style = escReverse
+		}
+		write(x-utf8.RuneCountInString(label)/2, y, label, style)
+	}
+
+	lines := make([]string, rows)
+	for y := range grid {
+		lines[y] = strings.TrimRight(strings.Join(grid[y], ""), " ")
+	}
+	return lines
+}
+
+// hearts draws a player's lives as full hearts, and the lives they have lost as empty hearts.
+func hearts(lives, max int) string {
+	return strings.Repeat("♥", utils.Max(lives, 0)) + strings.Repeat("♡", utils.Max(max-lives, 0))
+}
+
+// bigDigits is a five-line font for the digits 0-9 and a minus sign.
+var bigDigits = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {" █ ", "██ ", " █ ", " █ ", "███"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	'-': {"   ", "   ", "███", "   ", "   "},
+}
+
+// bigNumber draws a number in large digits.
+func bigNumber(n int) []string {
+	lines := make([]string, 5)
+	for i, r := range fmt.Sprint(n) {
+		for y := range lines {
+			if i > 0 {
+				lines[y] += " "
+			}
+			lines[y] += bigDigits[r][y]
+		}
+	}
+	return lines
+}
+
+// renderFaces draws cards side by side as boxes, numbering them and highlighting the selected card.
+// Cards with no rank are drawn face down.
+func renderFaces(h Hand, selected int) []string {
+	f := faces(h)
+	if 0 <= selected && selected < len(f) {
+		f[selected].Selected = true
+	}
+	lines := render.Renderer{Style: render.Boxes, Palette: cardRenderer.Palette}.Lines(f)
+	numbers := ""
+	for i := range h {
+		label := strconv.Itoa(i + 1)
+		if i == selected {
+			label += "^"
+		}
+		numbers += fmt.Sprintf("%-*s", render.BoxWidth+1, strings.Repeat(" ", render.BoxWidth/2)+label)
+	}
+	lines = append(lines, numbers)
+	for i := range lines {
+		lines[i] = " " + lines[i]
+	}
+	return lines
+}
@@ -0,0 +1,102 @@
+package cards
+
+import (
+	"shuffle/render"
+	"shuffle/utils"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		keys  []key
+	}{
+		{"", nil},
+		{"\x1b[A\x1b[B\x1b[C\x1b[D", []key{keyUp, keyDown, keyRight, keyLeft}},
+		{"\x1bOD", []key{keyLeft}},
+		{"\r\n", []key{keyEnter, keyEnter}},
+		{"\x03\x04", []key{keyQuit, keyQuit}},
+		{"12u", []key{'1', '2', 'u'}},
+		{"\x1b[Hq", []key{'q'}},
+	}
+	for _, test := range tests {
+		utils.Error(t, parseKeys([]byte(test.input)), test.keys, test.input)
+	}
+}
+
+func TestBigNumber(t *testing.T) {
+	utils.Error(t, bigNumber(7), []string{"███", "  █", "  █", "  █", "  █"})
+	lines := bigNumber(-10)
+	utils.Error(t, len(lines), 5)
+	utils.Error(t, lines[2], "███  █  █ █")
+}
+
+func TestDescribeNNEvent(t *testing.T) {
+	mgr := new(NNGameManager)
+	mgr.StartGame([]*NNPlayer{NewNNPlayer("Alice"), NewNNPlayer("Bob")}, 0, NNDefaultSettings)
+	tests := []struct {
+		event Event
+		line  string
+		ok    bool
+	}{
+		{Event{Type: EventCardPlayed, Seat: 1, Cards: []Card{NewCard(Nine, Clubs)}, Count: 42}, "Bob plays [9♣], count 42", true},
+		{Event{Type: EventBusted, Seat: 0, Count: 100}, "Alice busts at 100!", true},
+		{Event{Type: EventLifeLost, Seat: 0, Lives: 1}, "Alice has 1 life left", true},
+		{Event{Type: EventLifeLost, Seat: 0, Lives: 2}, "Alice has 2 lives left", true},
+		{Event{Type: EventDrew, Seat: 0}, "", false},
+	}
+	for _, test := range tests {
+		line, ok := describeNNEvent(tableNames(mgr.Table()), test.event)
+		utils.Error(t, line, test.line, string(test.event.Type))
+		utils.Error(t, ok, test.ok, string(test.event.Type))
+	}
+}
+
+func TestNNScreen(t *testing.T) {
+	mgr := new(NNGameManager)
+	players := []*NNPlayer{NewNNPlayer("Alice"), NewNNPlayer("Bob"), NewNNPlayer("Charlie")}
+	mgr.SetEvents(NewEventStream())
+	screen := newNNScreen(mgr)
+	mgr.Events().Subscribe(screen.record)
+	mgr.StartGame(players, 0, NNDefaultSettings)
+	players[0].ReplaceHand(Hand{NewCard(Two, Hearts), NewCard(Three, Clubs), NewCard(Nine, Spades)})
+	mgr.count = 42
+
+	frame := screen.render(80, 40)
+	utils.Error(t, strings.Count(frame, "\n") < 40, true, "frame fits the height")
+	utils.Error(t, strings.Contains(frame, "▶ Alice ♥♥♥"), true, "current player is marked")
+	utils.Error(t, strings.Contains(frame, "↻ clockwise"), true, "direction is shown")
+	utils.Error(t, strings.Contains(frame, "█ █ ███"), true, "count is shown in large digits")
+	utils.Error(t, strings.Contains(frame, "#=====#"), true, "selected card is highlighted")
+	utils.Error(t, strings.Contains(frame, "-> count"), false, "no hints unless asked for")
+	mgr.SetHints(true)
+	frame = screen.render(80, 40)
+	utils.Error(t, strings.Contains(frame, "-> count 44, next player forced to bust"), true, "hint for the selected card")
+
+	screen.press(players[0], keyRight)
+	utils.Error(t, screen.selected, 1, "right arrow")
+	screen.press(players[0], keyLeft)
+	screen.press(players[0], keyLeft)
+	utils.Error(t, screen.selected, 2, "left arrow wraps around")
+	screen.press(players[0], '2')
+	utils.Error(t, mgr.count, 45, "count after playing the second card")
+	utils.Error(t, mgr.table.CurrSeat(), 1, "next player")
+	utils.Error(t, screen.log[len(screen.log)-1], "Alice plays [3♣], count 45")
+
+	screen.press(players[1], 'q')
+	utils.Error(t, mgr.playing, false, "quit ends the game")
+}
+
+func TestRenderFacesPalette(t *testing.T) {
+	defer func(r render.Renderer, noColor bool) { cardRenderer, color.NoColor = r, noColor }(cardRenderer, color.NoColor)
+	color.NoColor = false
+	h := Hand{NewCard(Queen, Spades), NewCard(Ten, Hearts)}
+
+	SetCardPalette(render.TwoColour)
+	utils.Error(t, strings.Contains(strings.Join(renderFaces(h, 0), "\n"), "\x1b["), true, "cards are coloured")
+	SetCardPalette(render.NoColour)
+	utils.Error(t, strings.Contains(strings.Join(renderFaces(h, 0), "\n"), "\x1b["), false, "cards follow the palette")
+}
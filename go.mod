@@ -5,12 +5,10 @@ go 1.17
 require (
 	github.com/fatih/color v1.13.0
 	github.com/golang/mock v1.6.0
+	github.com/mattn/go-isatty v0.0.14
 	github.com/pkg/errors v0.9.1
+	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e
 	golang.org/x/text v0.3.7
 )
 
-require (
-	github.com/mattn/go-colorable v0.1.12 // indirect
-	github.com/mattn/go-isatty v0.0.14 // indirect
-	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
-)
+require github.com/mattn/go-colorable v0.1.12 // indirect
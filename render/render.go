@@ -0,0 +1,263 @@
+// Package render draws playing cards for the terminal: compactly on a single line,
+// as multi-line ASCII boxes, or as the glyphs of the Unicode Playing Cards block.
+// Cards are laid out side by side and wrapped to fit the width of the terminal.
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+)
+
+// Style is a way of drawing cards.
+type Style string
+
+const (
+	// Compact draws cards on a single line as their rank and suit symbol, e.g. "[Q♠, 9♣]".
+	Compact Style = "compact"
+	// Boxes draws cards as multi-line ASCII boxes, with the rank in opposite corners and the suit in the middle.
+	Boxes Style = "boxes"
+	// Glyphs draws cards as single characters from the Unicode Playing Cards block, e.g. "🂭 🃙".
+	Glyphs Style = "glyphs"
+	// Names draws cards on a single line as their full names, e.g. "Queen of Spades, Nine of Clubs",
+	// which screen readers can read aloud.
+	Names Style = "names"
+)
+
+// Styles lists every Style, in the order they are offered to players.
+var Styles = [4]Style{Compact, Boxes, Glyphs, Names}
+
+// Palette decides the colours cards are drawn in.
+type Palette int
+
+const (
+	// TwoColour draws hearts and diamonds in red, and clubs and spades in white.
+	TwoColour Palette = iota
+	// FourColour draws each suit in its own bright colour, so suits can be told apart at a glance:
+	// hearts in red, diamonds in blue, clubs in green and spades in white.
+	FourColour
+	// NoColour draws cards without colour.
+	NoColour
+)
+
+// DefaultPalette returns NoColour if the NO_COLOR environment variable is set to anything,
+// following https://no-color.org, and TwoColour otherwise.
+func DefaultPalette() Palette {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColour
+	}
+	return TwoColour
+}
+
+// ParseStyle converts the name of a Style to the Style.
+// It returns an error if there is no Style with that name.
+func ParseStyle(name string) (Style, error) {
+	for _, s := range Styles {
+		if string(s) == name {
+			return s, nil
+		}
+	}
+	return "", errors.Errorf("unknown card style %q", name)
+}
+
+// Face is what can be seen of a card.
+type Face struct {
+	Rank     string // the rank's symbol: A, 2-10, J, Q or K
+	Suit     string // the suit's symbol: ♠, ♥, ♦ or ♣
+	Red      bool   // whether the card is drawn in red
+	Name     string // the card's full name, such as "Queen of Spades", used by the Names style
+	Down     bool   // whether the card is face down, in which case only its back is drawn
+	Selected bool   // whether the card is highlighted, such as when a player is choosing it
+}
+
+// String converts the face to its rank and suit symbol, or "??" if it is face down.
+func (f Face) String() string {
+	if f.Down {
+		return "??"
+	}
+	return f.Rank + f.Suit
+}
+
+// Box dimensions, in terminal columns and lines.
+const (
+	BoxWidth  = 7
+	BoxHeight = 5
+	gap       = 1 // the number of columns between cards side by side
+)
+
+// Renderer draws cards in a Style and Palette, wrapping them to a width.
+type Renderer struct {
+	Style   Style
+	Palette Palette
+	Width   int // the number of terminal columns to fit the cards into, or 0 to never wrap
+}
+
+// Render draws the cards, laid out side by side in as many rows as needed to fit the width.
+// Compact cards are enclosed in brackets and separated by commas, and are never wrapped,
+// so that the result can be read back. Named cards are separated by commas and never wrapped.
+func (r Renderer) Render(faces []Face) string {
+	return strings.Join(r.Lines(faces), "\n")
+}
+
+// Lines draws the cards like Render, returning each line of the drawing separately.
+func (r Renderer) Lines(faces []Face) []string {
+	switch r.Style {
+	case Boxes:
+		return r.wrap(faces, BoxWidth, r.box)
+	case Glyphs:
+		// Some terminals draw the glyphs two columns wide, so leave room for them.
+		return r.wrap(faces, 2, func(f Face) []string {
+			return []string{r.Paint(f, string(Glyph(f)))}
+		})
+	case Names:
+		str := make([]string, len(faces))
+		for i, f := range faces {
+			str[i] = r.Paint(f, f.Name)
+		}
+		return []string{strings.Join(str, ", ")}
+	default:
+		str := make([]string, len(faces))
+		for i, f := range faces {
+			str[i] = r.Paint(f, f.String())
+		}
+		return []string{fmt.Sprintf("[%s]", strings.Join(str, ", "))}
+	}
+}
+
+// Inline draws a single card to fit in a sentence: by its full name in the Names style,
+// and by its rank and suit symbol otherwise.
+func (r Renderer) Inline(f Face) string {
+	if r.Style == Names {
+		return r.Paint(f, f.Name)
+	}
+	return r.Paint(f, f.String())
+}
+
+// wrap lays out cards drawn by the draw function side by side, starting a new row of cards
+// whenever the next card would not fit within the width. Every card occupies the same number of columns.
+func (r Renderer) wrap(faces []Face, width int, draw func(Face) []string) []string {
+	perRow := len(faces)
+	if r.Width > 0 {
+		perRow = (r.Width + gap) / (width + gap)
+		if perRow < 1 {
+			perRow = 1
+		}
+	}
+	var lines []string
+	for start := 0; start < len(faces); start += perRow {
+		end := start + perRow
+		if end > len(faces) {
+			end = len(faces)
+		}
+		var row []string
+		for i, f := range faces[start:end] {
+			for y, line := range draw(f) {
+				if y == len(row) {
+					row = append(row, "")
+				}
+				if i > 0 {
+					row[y] += strings.Repeat(" ", gap)
+				}
+				row[y] += line
+			}
+		}
+		lines = append(lines, row...)
+	}
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	return lines
+}
+
+// box draws a card as an ASCII box, or its back if it is face down.
+// Selected cards are drawn with a heavier border.
+func (r Renderer) box(f Face) []string {
+	corner, edge, side := "+", "-", "|"
+	if f.Selected {
+		corner, edge, side = "#", "=", "#"
+	}
+	top := corner + strings.Repeat(edge, BoxWidth-2) + corner
+	if f.Down {
+		back := side + strings.Repeat("/", BoxWidth-2) + side
+		return []string{top, back, back, back, top}
+	}
+	inner := BoxWidth - 2
+	return []string{
+		top,
+		side + r.Paint(f, fmt.Sprintf("%-*s", inner, f.Rank)) + side,
+		side + r.Paint(f, center(f.Suit, inner)) + side,
+		side + r.Paint(f, fmt.Sprintf("%*s", inner, f.Rank)) + side,
+		top,
+	}
+}
+
+// center pads the text with spaces on both sides to fill the width.
+func center(text string, width int) string {
+	n := len([]rune(text))
+	left := (width - n) / 2
+	return strings.Repeat(" ", left) + text + strings.Repeat(" ", width-n-left)
+}
+
+// Paint colours text describing a card in the colour of its suit in the Palette.
+// Face-down cards are not coloured.
+func (r Renderer) Paint(f Face, text string) string {
+	if f.Down || r.Palette == NoColour {
+		return text
+	}
+	if r.Palette == FourColour {
+		switch f.Suit {
+		case "♥":
+			return color.New(color.FgHiRed, color.Bold).Sprint(text)
+		case "♦":
+			return color.New(color.FgHiBlue, color.Bold).Sprint(text)
+		case "♣":
+			return color.New(color.FgHiGreen, color.Bold).Sprint(text)
+		default:
+			return color.New(color.FgHiWhite, color.Bold).Sprint(text)
+		}
+	}
+	if f.Red {
+		return color.New(color.FgRed).Sprint(text)
+	}
+	return color.New(color.FgWhite).Sprint(text)
+}
+
+// Card glyphs are laid out in the Unicode Playing Cards block in rows of 16 code points per suit,
+// starting from the back of a card, followed by the ace to the king. The knight, which is only
+// found in tarot decks, sits between the jack and the queen.
+const (
+	glyphBack     = '\U0001F0A0'
+	glyphSpades   = '\U0001F0A0'
+	glyphHearts   = '\U0001F0B0'
+	glyphDiamonds = '\U0001F0C0'
+	glyphClubs    = '\U0001F0D0'
+)
+
+// glyphRanks gives the position of each rank within its suit's row of the Playing Cards block.
+var glyphRanks = map[string]rune{
+	"A": 0x1, "2": 0x2, "3": 0x3, "4": 0x4, "5": 0x5, "6": 0x6, "7": 0x7,
+	"8": 0x8, "9": 0x9, "10": 0xA, "J": 0xB, "Q": 0xD, "K": 0xE,
+}
+
+// Glyph returns the character from the Unicode Playing Cards block for the card, e.g. '🂡' for the ace of spades.
+// Face-down cards, and cards without a glyph, are drawn as the back of a card, '🂠'.
+func Glyph(f Face) rune {
+	rank, ok := glyphRanks[f.Rank]
+	if f.Down || !ok {
+		return glyphBack
+	}
+	switch f.Suit {
+	case "♠":
+		return glyphSpades + rank
+	case "♥":
+		return glyphHearts + rank
+	case "♦":
+		return glyphDiamonds + rank
+	case "♣":
+		return glyphClubs + rank
+	}
+	return glyphBack
+}
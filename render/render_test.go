@@ -0,0 +1,123 @@
+package render
+
+import (
+	"os"
+	"shuffle/utils"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+var (
+	queenOfSpades = Face{Rank: "Q", Suit: "♠"}
+	tenOfHearts   = Face{Rank: "10", Suit: "♥", Red: true}
+	faceDown      = Face{Down: true}
+)
+
+func TestMain(m *testing.M) {
+	color.NoColor = true
+	m.Run()
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, s := range Styles {
+		got, err := ParseStyle(string(s))
+		utils.Error(t, err, nil)
+		utils.Error(t, got, s)
+	}
+	_, err := ParseStyle("fancy")
+	utils.Error(t, err != nil, true, "error for an unknown style")
+}
+
+func TestCompact(t *testing.T) {
+	r := Renderer{Style: Compact, Width: 4}
+	utils.Error(t, r.Render(nil), "[]")
+	utils.Error(t, r.Render([]Face{queenOfSpades, tenOfHearts, faceDown}), "[Q♠, 10♥, ??]", "never wrapped")
+}
+
+func TestBoxes(t *testing.T) {
+	r := Renderer{Style: Boxes}
+	utils.Error(t, r.Lines([]Face{tenOfHearts, faceDown}), []string{
+		"+-----+ +-----+",
+		"|10   | |/////|",
+		"|  ♥  | |/////|",
+		"|   10| |/////|",
+		"+-----+ +-----+",
+	})
+
+	selected := queenOfSpades
+	selected.Selected = true
+	utils.Error(t, r.Lines([]Face{selected})[2], "#  ♠  #", "selected card")
+}
+
+func TestWrap(t *testing.T) {
+	hand := []Face{queenOfSpades, tenOfHearts, faceDown, queenOfSpades, tenOfHearts}
+	tests := []struct {
+		style Style
+		width int
+		lines int
+	}{
+		{Boxes, 0, BoxHeight},
+		{Boxes, 80, BoxHeight},
+		{Boxes, 39, BoxHeight},
+		{Boxes, 38, 2 * BoxHeight},
+		{Boxes, 15, 3 * BoxHeight},
+		{Boxes, 3, 5 * BoxHeight},
+		{Glyphs, 0, 1},
+		{Glyphs, 6, 3},
+	}
+	for _, test := range tests {
+		lines := Renderer{Style: test.style, Width: test.width}.Lines(hand)
+		utils.Error(t, len(lines), test.lines, string(test.style))
+		for _, line := range lines {
+			if test.width > 0 && test.style == Boxes {
+				utils.Error(t, len([]rune(line)) <= utils.Max(test.width, BoxWidth), true, line)
+			}
+		}
+	}
+}
+
+func TestGlyph(t *testing.T) {
+	tests := []struct {
+		face  Face
+		glyph rune
+	}{
+		{Face{Rank: "A", Suit: "♠"}, '🂡'},
+		{queenOfSpades, '🂭'},
+		{tenOfHearts, '🂺'},
+		{Face{Rank: "K", Suit: "♦"}, '🃎'},
+		{Face{Rank: "9", Suit: "♣"}, '🃙'},
+		{faceDown, '🂠'},
+		{Face{Rank: "X", Suit: "♣"}, '🂠'},
+	}
+	for _, test := range tests {
+		utils.Error(t, Glyph(test.face), test.glyph, test.face.String())
+	}
+	utils.Error(t, Renderer{Style: Glyphs}.Render([]Face{queenOfSpades, faceDown}), "🂭 🂠")
+	utils.Error(t, strings.Count(Renderer{Style: Glyphs, Width: 6}.Render([]Face{queenOfSpades, faceDown, tenOfHearts}), "\n"), 1)
+}
+
+func TestPalette(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	utils.Error(t, DefaultPalette(), NoColour)
+	os.Unsetenv("NO_COLOR")
+	utils.Error(t, DefaultPalette(), TwoColour)
+
+	color.NoColor = false
+	defer func() { color.NoColor = true }()
+	diamond := Face{Rank: "K", Suit: "♦", Red: true}
+	utils.Error(t, Renderer{Palette: TwoColour}.Paint(diamond, "K♦"), "\x1b[31mK♦\x1b[0m")
+	utils.Error(t, Renderer{Palette: FourColour}.Paint(diamond, "K♦"), "\x1b[94;1mK♦\x1b[0m", "diamonds are blue")
+	utils.Error(t, Renderer{Palette: FourColour}.Paint(Face{Rank: "K", Suit: "♣"}, "K♣"), "\x1b[92;1mK♣\x1b[0m", "clubs are green")
+	utils.Error(t, Renderer{Palette: NoColour}.Paint(diamond, "K♦"), "K♦")
+	utils.Error(t, Renderer{Palette: FourColour}.Paint(faceDown, "??"), "??")
+}
+
+func TestNames(t *testing.T) {
+	r := Renderer{Style: Names, Width: 4}
+	named := []Face{{Rank: "Q", Suit: "♠", Name: "Queen of Spades"}, {Down: true, Name: "a face-down card"}}
+	utils.Error(t, r.Render(named), "Queen of Spades, a face-down card", "never wrapped")
+	utils.Error(t, r.Inline(named[0]), "Queen of Spades")
+	utils.Error(t, Renderer{Style: Boxes}.Inline(named[0]), "Q♠")
+}
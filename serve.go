@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"shuffle/cards"
+)
+
+// serve runs a lobby of networked games of 99, which people join over plain TCP connections with nc or telnet.
+func serve(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":9999", "address to listen for players on")
+	seats := fs.Int("seats", cards.LobbyDefaults.Seats, "number of seats at quick-match tables")
+	robotWait := fs.Duration("robotwait", cards.LobbyDefaults.RobotWait, "how long quick-match tables wait for people before robots fill the empty seats")
+	robotDelay := fs.Duration("robotdelay", cards.LobbyDefaults.RobotDelay, "how long robots pause before playing")
+	grace := fs.Duration("grace", cards.LobbyDefaults.Grace, "how long the seats of players who lose their connection are held for them")
+	idle := fs.Duration("idle", cards.LobbyDefaults.Idle, "how long a table with nobody seated stays open, or 0 to keep it open")
+	turnLimit := fs.Duration("turnlimit", 0, "the most time a player may take over their turn, e.g. 30s (0 for no limit)")
+	onTimeout := fs.String("ontimeout", string(cards.TimeoutSafestCard), "what happens to players who run out of time: random, safest or forfeit")
+	filter := fs.Bool("filter", cards.ChatDefaults.Filter, "mask profanity in chat")
+	logPath := fs.String("log", "", "file to append the events of every table, including chat, to, as JSON Lines")
+	fs.Parse(args)
+
+	settings := *cards.NNDefaultSettings
+	settings.TurnLimit = *turnLimit
+	settings.OnTimeout = cards.NNTimeoutAction(*onTimeout)
+	if *turnLimit < 0 {
+		return fmt.Errorf("-turnlimit must not be negative")
+	} else if !settings.OnTimeout.Valid() {
+		return fmt.Errorf("unknown timeout action %q", *onTimeout)
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	cfg := cards.LobbyConfig{Seats: *seats, RobotWait: *robotWait, RobotDelay: *robotDelay, Grace: *grace, Idle: *idle, Settings: &settings, Chat: cards.ChatDefaults}
+	cfg.Chat.Filter = *filter
+	if *logPath != "" {
+		log, err := cards.OpenEventLog(*logPath)
+		if err != nil {
+			return err
+		}
+		defer log.Close()
+		cfg.Log = log
+	}
+	lobby := cards.NewLobby(cfg)
+	defer lobby.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "Serving 99 on %v\n", l.Addr())
+	return cards.ServeLobby(ctx, l, lobby)
+}
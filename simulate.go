@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"shuffle/cards"
+	"strings"
+)
+
+// simulate plays many games of 99 between robots and prints statistics about them.
+func simulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	games := fs.Int("games", 1000, "number of games to play")
+	workers := fs.Int("workers", runtime.NumCPU(), "number of games to play in parallel")
+	seed := fs.Int64("seed", 1, "seed for shuffling and robot decisions")
+	strategies := fs.String("strategies", "cautious,aggressive,random,random",
+		"comma-separated strategy for each seat: "+strings.Join(cards.NNStrategyNames(), ", ")+", or "+cards.BotPrefix+"<command> for an external bot")
+	format := fs.String("format", "text", "output format: text, csv or json")
+	settings := settingsFlags(fs)
+	fs.Parse(args)
+
+	set, err := settings()
+	if err != nil {
+		return err
+	}
+	report, err := cards.Simulate(context.Background(), cards.SimConfig{
+		Games:      *games,
+		Workers:    *workers,
+		Seed:       *seed,
+		Settings:   set,
+		Strategies: strings.Split(*strategies, ","),
+	})
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+// settingsFlags defines flags for each of the rules of 99 on the flag set.
+// The returned function builds the settings once the flags are parsed,
+// and returns an error if any of them are out of range.
+func settingsFlags(fs *flag.FlagSet) func() (*cards.NNGameSettings, error) {
+	def := cards.NNDefaultSettings
+	cardsEach := fs.Int("cards", def.CardsPerPlayer, "cards dealt to each player")
+	lives := fs.Int("lives", def.LivesPerPlayer, "lives per player")
+	max := fs.Int("max", def.MaxCount, "highest count before busting")
+	reverse := fs.String("reverse", def.WildCards.Reverse.String(), "rank that reverses play")
+	ninetyNine := fs.String("ninetynine", def.WildCards.NinetyNine.String(), "rank that takes the count to the maximum")
+	minusTen := fs.String("minusten", def.WildCards.MinusTen.String(), "rank worth -10")
+	zero := fs.String("zero", def.WildCards.Zero.String(), "rank worth 0")
+
+	return func() (*cards.NNGameSettings, error) {
+		switch {
+		case *cardsEach < 1:
+			return nil, fmt.Errorf("-cards must be at least 1")
+		case *lives < 1:
+			return nil, fmt.Errorf("-lives must be at least 1")
+		case *max < 1:
+			return nil, fmt.Errorf("-max must be at least 1")
+		}
+		set := &cards.NNGameSettings{CardsPerPlayer: *cardsEach, LivesPerPlayer: *lives, MaxCount: *max}
+		for _, wild := range []struct {
+			rank *cards.Rank
+			text string
+		}{
+			{&set.WildCards.Reverse, *reverse},
+			{&set.WildCards.NinetyNine, *ninetyNine},
+			{&set.WildCards.MinusTen, *minusTen},
+			{&set.WildCards.Zero, *zero},
+		} {
+			r, err := cards.ParseRank(wild.text)
+			if err != nil {
+				return nil, err
+			}
+			*wild.rank = r
+		}
+		return set, nil
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"shuffle/cards"
+	"strings"
+)
+
+// tournament plays a tournament of 99 between robots and prints the leaderboard.
+func tournament(args []string) error {
+	fs := flag.NewFlagSet("tournament", flag.ExitOnError)
+	entrants := fs.String("entrants", "cautious,aggressive,random,expert",
+		"comma-separated strategy for each entrant: "+strings.Join(cards.NNStrategyNames(), ", ")+", or "+cards.BotPrefix+"<command> for an external bot")
+	seats := fs.Int("seats", 0, "players at each table (default 4, or the number of entrants if fewer)")
+	pairing := fs.String("pairing", cards.TournamentRoundRobin, "how tables are drawn: "+cards.TournamentRoundRobin+" or "+cards.TournamentSwiss)
+	rounds := fs.Int("rounds", 0, "rounds to play (default 1 for round robin, 3 for Swiss)")
+	seed := fs.Int64("seed", 1, "master seed for shuffling and robot decisions")
+	format := fs.String("format", "text", "output format: text, csv or json")
+	settings := settingsFlags(fs)
+	fs.Parse(args)
+
+	set, err := settings()
+	if err != nil {
+		return err
+	}
+	report, err := cards.RunTournament(context.Background(), cards.TournamentConfig{
+		Entrants: strings.Split(*entrants, ","),
+		Seats:    *seats,
+		Format:   *pairing,
+		Rounds:   *rounds,
+		Seed:     *seed,
+		Settings: set,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		return report.WriteText(os.Stdout)
+	case "csv":
+		return report.WriteCSV(os.Stdout)
+	case "json":
+		return report.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}